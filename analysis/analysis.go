@@ -0,0 +1,193 @@
+// Package analysis summarizes reward estimates for deciding when to stop an experiment.
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/numint"
+)
+
+const (
+	defaultLevel       = 0.95
+	quantileTol        = 1e-9
+	maxQuantileBisects = 200
+)
+
+// NewAnalyzer returns a new Analyzer with any Option arguments applied.
+// By default, integrals are computed with numint.NewQuadrature() and intervals are 95% credible intervals.
+func NewAnalyzer(opts ...Option) *Analyzer {
+	a := &Analyzer{
+		integrator: numint.NewQuadrature(),
+		level:      defaultLevel,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Analyzer computes the probability that each arm is best, the expected loss of choosing each arm,
+// and a credible interval for each arm's reward.
+// The reward distributions must be continuous, as for Thompson sampling. Null arms are allowed and are never best.
+type Analyzer struct {
+	integrator mab.Integrator
+	level      float64
+}
+
+// An Interval is a credible interval for an arm's reward.
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// ArmSummary contains the analysis of a single arm.
+// ExpectedLoss is E[max_j X_j - X_arm], the expected reward given up by choosing this arm instead of the best arm.
+// Null arms have Null set to true and all other fields zero, and only the arm index and the flag are encoded to JSON.
+type ArmSummary struct {
+	Arm          int      `json:"arm"`
+	Null         bool     `json:"null,omitempty"`
+	Mean         float64  `json:"mean"`
+	ProbBest     float64  `json:"prob_best"`
+	ExpectedLoss float64  `json:"expected_loss"`
+	Interval     Interval `json:"interval"`
+}
+
+// MarshalJSON returns the JSON encoding of the summary, without the numeric fields for a Null arm.
+func (s ArmSummary) MarshalJSON() ([]byte, error) {
+	if s.Null {
+		return json.Marshal(struct {
+			Arm  int  `json:"arm"`
+			Null bool `json:"null"`
+		}{s.Arm, true})
+	}
+	type summary ArmSummary
+	return json.Marshal(summary(s))
+}
+
+// Report contains an ArmSummary for each arm, in arm order.
+// ExpectedMax is the expected reward of the best arm, E[max_j X_j].
+type Report struct {
+	Arms        []ArmSummary `json:"arms"`
+	ExpectedMax float64      `json:"expected_max"`
+	Level       float64      `json:"level"`
+}
+
+// Analyze computes the analysis of the reward estimates.
+// Returns an error if any non-Null arm has a point distribution, if all arms are Null, or if an integral fails.
+func (a *Analyzer) Analyze(rewards []mab.Dist) (Report, error) {
+	report := Report{
+		Arms:  make([]ArmSummary, len(rewards)),
+		Level: a.level,
+	}
+
+	active := make([]int, 0, len(rewards))
+	for arm, dist := range rewards {
+		report.Arms[arm].Arm = arm
+		if isNull(dist) {
+			report.Arms[arm].Null = true
+			continue
+		}
+		if _, ok := dist.(mab.PointDist); ok {
+			return report, fmt.Errorf("arm %d: point distributions cannot be analyzed", arm)
+		}
+		active = append(active, arm)
+	}
+
+	if len(active) == 0 {
+		return report, fmt.Errorf("all arms are null")
+	}
+
+	lowerQ, upperQ := (1-a.level)/2, 1-(1-a.level)/2
+
+	for _, arm := range active {
+		dist := rewards[arm]
+		lo, hi := dist.Support()
+
+		mean, err := a.integrator.Integrate(func(x float64) float64 { return x * dist.Prob(x) }, lo, hi)
+		if err != nil {
+			return report, fmt.Errorf("arm %d: %w", arm, err)
+		}
+
+		others := func(x float64) float64 {
+			total := 1.0
+			for _, j := range active {
+				if j != arm {
+					total *= rewards[j].CDF(x)
+				}
+			}
+			return total
+		}
+
+		probBest, err := a.integrator.Integrate(func(x float64) float64 { return dist.Prob(x) * others(x) }, lo, hi)
+		if err != nil {
+			return report, fmt.Errorf("arm %d: %w", arm, err)
+		}
+
+		// E[max] is the sum over arms of E[X_arm; arm is best]
+		maxPart, err := a.integrator.Integrate(func(x float64) float64 { return x * dist.Prob(x) * others(x) }, lo, hi)
+		if err != nil {
+			return report, fmt.Errorf("arm %d: %w", arm, err)
+		}
+
+		report.ExpectedMax += maxPart
+		report.Arms[arm].Mean = mean
+		report.Arms[arm].ProbBest = probBest
+		report.Arms[arm].Interval = Interval{Quantile(dist, lowerQ), Quantile(dist, upperQ)}
+	}
+
+	for _, arm := range active {
+		report.Arms[arm].ExpectedLoss = math.Max(0, report.ExpectedMax-report.Arms[arm].Mean)
+	}
+
+	return report, nil
+}
+
+// Best returns the arm with the smallest expected loss, ignoring Null arms. Returns -1 if every arm is Null.
+func (r Report) Best() int {
+	best := -1
+	for arm, s := range r.Arms {
+		if !s.Null && (best < 0 || s.ExpectedLoss < r.Arms[best].ExpectedLoss) {
+			best = arm
+		}
+	}
+	return best
+}
+
+// Winner implements a stopping rule based on expected loss.
+// It returns the arm with the smallest expected loss and true if that loss is below threshold.
+// Otherwise, the experiment should continue, and it returns the same arm and false.
+func (r Report) Winner(threshold float64) (int, bool) {
+	best := r.Best()
+	if best < 0 {
+		return -1, false
+	}
+	return best, r.Arms[best].ExpectedLoss < threshold
+}
+
+// Quantile returns the q-th quantile of dist, found by bisection of its CDF over its Support.
+func Quantile(dist mab.Dist, q float64) float64 {
+	lo, hi := dist.Support()
+	if q <= dist.CDF(lo) {
+		return lo
+	}
+	if q >= dist.CDF(hi) {
+		return hi
+	}
+	for i := 0; i < maxQuantileBisects && hi-lo > quantileTol; i++ {
+		mid := lo + (hi-lo)/2
+		if dist.CDF(mid) < q {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo + (hi-lo)/2
+}
+
+func isNull(dist mab.Dist) bool {
+	p, ok := dist.(mab.PointDist)
+	return ok && math.IsInf(p.Mu, -1)
+}
@@ -0,0 +1,131 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/analysis"
+)
+
+func TestAnalyzer_Analyze_Normal(t *testing.T) {
+	report, err := analysis.NewAnalyzer().Analyze([]mab.Dist{mab.Normal(0, 1), mab.Normal(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Y-X ~ Normal(1, sqrt(2)), so P(Y>X) = Phi(1/sqrt(2)) and E[max(X,Y)] = Phi(1/sqrt(2)) + sqrt(2)*phi(1/sqrt(2))
+	probBest := 0.760250
+	expectedMax := probBest + math.Sqrt2*math.Exp(-0.25)/math.Sqrt(2*math.Pi)
+
+	expected := []struct{ mean, probBest, loss, lower, upper float64 }{
+		{0, 1 - probBest, expectedMax, -1.959964, 1.959964},
+		{1, probBest, expectedMax - 1, 1 - 1.959964, 1 + 1.959964},
+	}
+
+	for arm, e := range expected {
+		s := report.Arms[arm]
+		if s.Arm != arm {
+			t.Errorf("arm %d: wrong arm index %d", arm, s.Arm)
+		}
+		assertClose(t, "mean", e.mean, s.Mean, 1e-3)
+		assertClose(t, "prob best", e.probBest, s.ProbBest, 1e-3)
+		assertClose(t, "expected loss", e.loss, s.ExpectedLoss, 1e-3)
+		assertClose(t, "lower", e.lower, s.Interval.Lower, 1e-4)
+		assertClose(t, "upper", e.upper, s.Interval.Upper, 1e-4)
+	}
+	assertClose(t, "expected max", expectedMax, report.ExpectedMax, 1e-3)
+
+	if report.Best() != 1 {
+		t.Errorf("expected arm 1 to be best. got=%d", report.Best())
+	}
+}
+
+func TestAnalyzer_Analyze_Null(t *testing.T) {
+	report, err := analysis.NewAnalyzer(analysis.WithLevel(0.9)).Analyze([]mab.Dist{mab.Beta(20, 80), mab.Null(), mab.Beta(30, 70)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	null := report.Arms[1]
+	if null != (analysis.ArmSummary{Arm: 1, Null: true}) {
+		t.Errorf("unexpected summary for null arm: %+v", null)
+	}
+	if report.Best() != 2 {
+		t.Errorf("expected arm 2 to be best. got=%d", report.Best())
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"arm":1,"null":true}`) {
+		t.Errorf("expected the null arm without numeric fields in %s", data)
+	}
+
+	total := report.Arms[0].ProbBest + report.Arms[2].ProbBest
+	assertClose(t, "total prob best", 1, total, 1e-3)
+	assertClose(t, "mean", 0.2, report.Arms[0].Mean, 1e-4)
+	if report.Level != 0.9 {
+		t.Errorf("expected level 0.9. got=%f", report.Level)
+	}
+	if !(report.Arms[2].Interval.Lower < 0.3 && 0.3 < report.Arms[2].Interval.Upper) {
+		t.Errorf("interval does not contain the mean: %+v", report.Arms[2].Interval)
+	}
+}
+
+func TestAnalyzer_Analyze_Errors(t *testing.T) {
+	tests := map[string][]mab.Dist{
+		"point":    {mab.Beta(1, 1), mab.Point(0.5)},
+		"all null": {mab.Null(), mab.Null()},
+	}
+
+	for name, rewards := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := analysis.NewAnalyzer().Analyze(rewards); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestReport_Winner(t *testing.T) {
+	analyzer := analysis.NewAnalyzer()
+
+	tests := []struct {
+		name    string
+		rewards []mab.Dist
+		winner  int
+		stop    bool
+	}{
+		{"clear winner", []mab.Dist{mab.Beta(10, 1000), mab.Beta(1000, 10)}, 1, true},
+		{"too close", []mab.Dist{mab.Beta(10, 10), mab.Beta(11, 10)}, 1, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := analyzer.Analyze(test.rewards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			winner, stop := report.Winner(0.001)
+			if winner != test.winner || stop != test.stop {
+				t.Errorf("expected (%d, %v). got=(%d, %v)", test.winner, test.stop, winner, stop)
+			}
+		})
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	assertClose(t, "median", 0.5, analysis.Quantile(mab.Beta(5, 5), 0.5), 1e-6)
+	assertClose(t, "below support", -4, analysis.Quantile(mab.Normal(0, 1), 0), 1e-9)
+}
+
+func assertClose(t *testing.T, name string, expected, actual, tol float64) {
+	t.Helper()
+	if math.Abs(expected-actual) > tol {
+		t.Errorf("%s: expected %f. got=%f", name, expected, actual)
+	}
+}
@@ -0,0 +1,21 @@
+package analysis
+
+import "github.com/stitchfix/mab"
+
+// Option is a function that can be passed to NewAnalyzer to override the default settings.
+type Option func(*Analyzer)
+
+// WithIntegrator sets the Integrator used to compute means, probabilities of being best, and expected losses.
+// The default is numint.NewQuadrature().
+func WithIntegrator(i mab.Integrator) Option {
+	return func(a *Analyzer) {
+		a.integrator = i
+	}
+}
+
+// WithLevel sets the probability mass of the credible intervals. The default is 0.95.
+func WithLevel(level float64) Option {
+	return func(a *Analyzer) {
+		a.level = level
+	}
+}
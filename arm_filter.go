@@ -0,0 +1,82 @@
+package mab
+
+import (
+	"context"
+	"fmt"
+)
+
+// An ArmFilter excludes arms that are ineligible for a unit and bandit context, for example because of inventory or
+// legal constraints.
+// FilterArms returns the excluded arms, each with the reason it was excluded.
+// Excluded arms are set to Null before the Strategy runs, so the selection probabilities are renormalized over the
+// eligible arms.
+type ArmFilter interface {
+	FilterArms(ctx context.Context, unit string, banditContext interface{}, numArms int) ([]FilteredArm, error)
+}
+
+// ArmFilterFunc is an adapter to allow a normal function to be used as an ArmFilter.
+type ArmFilterFunc func(ctx context.Context, unit string, banditContext interface{}, numArms int) ([]FilteredArm, error)
+
+func (f ArmFilterFunc) FilterArms(ctx context.Context, unit string, banditContext interface{}, numArms int) ([]FilteredArm, error) {
+	return f(ctx, unit, banditContext, numArms)
+}
+
+// A FilteredArm is an arm that was excluded by an ArmFilter.
+type FilteredArm struct {
+	Arm    int    `json:"arm"`
+	Reason string `json:"reason"`
+}
+
+// EligibilityMask returns an ArmFilter that excludes every arm that is false in eligible, with the given reason.
+// Arms beyond the end of the mask are eligible.
+func EligibilityMask(reason string, eligible ...bool) ArmFilter {
+	return ArmFilterFunc(func(ctx context.Context, unit string, banditContext interface{}, numArms int) ([]FilteredArm, error) {
+		var filtered []FilteredArm
+		for arm, ok := range eligible {
+			if !ok && arm < numArms {
+				filtered = append(filtered, FilteredArm{arm, reason})
+			}
+		}
+		return filtered, nil
+	})
+}
+
+// filterArms applies the filters in order and returns a copy of rewards with the excluded arms set to Null.
+// An arm excluded by more than one filter is recorded with the reason from the first.
+func filterArms(ctx context.Context, filters []ArmFilter, unit string, banditContext interface{}, rewards []Dist) ([]Dist, []FilteredArm, error) {
+	if len(filters) == 0 {
+		return rewards, nil, nil
+	}
+
+	var filtered []FilteredArm
+	excluded := make(map[int]bool)
+
+	for _, filter := range filters {
+		arms, err := filter.FilterArms(ctx, unit, banditContext, len(rewards))
+		if err != nil {
+			return rewards, filtered, fmt.Errorf("arm filter: %w", err)
+		}
+		for _, f := range arms {
+			if f.Arm < 0 || f.Arm >= len(rewards) {
+				return rewards, filtered, Errorf(ErrInvalidParameter, "arm filter: arm %d out of range for %d arms", f.Arm, len(rewards))
+			}
+			if excluded[f.Arm] {
+				continue
+			}
+			excluded[f.Arm] = true
+			filtered = append(filtered, f)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return rewards, nil, nil
+	}
+
+	result := make([]Dist, len(rewards))
+	copy(result, rewards)
+	for _, f := range filtered {
+		result[f.Arm] = Null()
+	}
+
+	return result, filtered, nil
+}
@@ -2,14 +2,34 @@ package mab
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 )
 
 // A Bandit gets reward values from a RewardSource, computes selection probabilities using a Strategy, and selects
 // an arm using a Sampler.
+// If an Overrider is set, units that match an override get the forced arm without consulting the other components.
+// If a Holdout is set, units in the holdout get its control arm or a uniformly random arm instead.
+// If any ArmFilters are set, the arms they exclude are set to Null before the Strategy runs.
+// If a DecisionLogger is set, every call to SelectArm is logged, including calls that return an error.
+// If Instrumentation is set, it receives the timing and outcome of each stage of SelectArm.
+// Name and Version are optional, and are recorded in logged decisions to identify the bandit and the configuration
+// that produced them.
 type Bandit struct {
 	RewardSource
 	Strategy
 	Sampler
+
+	Overrides Overrider
+	Holdout   *Holdout
+	Filters   []ArmFilter
+	Logger    DecisionLogger
+
+	Instrumentation Instrumentation
+
+	Name    string
+	Version string
 }
 
 // SelectArm gets the current reward estimates, computes the arm selection probabilities, and selects and arm index.
@@ -22,34 +42,123 @@ type Bandit struct {
 // The banditContext argument is used to pass bandit context features to the reward source for contextual bandits.
 // The unit argument is a string that will be hashed to select an arm with the pseudo-random sampler.
 // SelectArm is deterministic for a fixed unit and set of reward estimates from the RewardSource.
+// Each result is given a unique DecisionID that can be used to join observed rewards to the logged decision.
+// If the Bandit's Overrider matches the unit and bandit context, the result has the forced arm, Forced set to true, and
+// no reward estimates or probabilities. Forced decisions are still logged, but they have zero propensity, so they are
+// not used for off-policy evaluation.
+// Units in the Bandit's Holdout have Holdout set to true. They get the control arm with no probabilities, or, for a
+// uniform holdout, an arm sampled from equal probabilities over the eligible arms. The control arm is not subject to the
+// ArmFilters, so that held-out units keep a fixed experience, but an error is returned if it is out of range of the
+// reward estimates.
 func (b *Bandit) SelectArm(ctx context.Context, unit string, banditContext interface{}) (Result, error) {
+	inst := b.instrumentation()
+	start := time.Now()
+	ctx = inst.StartSelect(ctx, b.Name, unit)
+
+	res, err := b.selectArm(ctx, inst, unit, banditContext)
+
+	inst.EndSelect(ctx, SelectEvent{
+		Bandit:   b.Name,
+		Version:  b.Version,
+		Unit:     unit,
+		Result:   res,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	if b.Logger != nil {
+		d := NewDecision(unit, banditContext, res, err)
+		d.Bandit, d.Version = b.Name, b.Version
+		b.Logger.LogDecision(ctx, d)
+	}
+	return res, err
+}
+
+func (b *Bandit) selectArm(ctx context.Context, inst Instrumentation, unit string, banditContext interface{}) (Result, error) {
 
 	res := Result{
-		Rewards: make([]Dist, 0),
-		Probs:   make([]float64, 0),
-		Arm:     -1,
+		DecisionID: NewDecisionID(),
+		Rewards:    make([]Dist, 0),
+		Probs:      make([]float64, 0),
+		Arm:        -1,
+	}
+
+	if b.Overrides != nil {
+		var arm int
+		var ok bool
+		_ = b.stage(ctx, inst, StageOverride, func(ctx context.Context) error {
+			arm, ok = b.Overrides.Override(ctx, unit, banditContext)
+			return nil
+		})
+		if ok {
+			res.Arm = arm
+			res.Forced = true
+			return res, nil
+		}
 	}
 
-	rewards, err := b.GetRewards(ctx, banditContext)
+	res.Holdout = b.Holdout != nil && b.Holdout.Contains(unit)
+
+	var rewards []Dist
+	err := b.stage(ctx, inst, StageRewards, func(ctx context.Context) (err error) {
+		rewards, err = b.GetRewards(ctx, banditContext)
+		return err
+	})
 	if err != nil {
 		return res, err
 	}
 
 	res.Rewards = rewards
 
-	probs, err := b.ComputeProbs(rewards)
+	if res.Holdout && !b.Holdout.Uniform {
+		err = b.stage(ctx, inst, StageProbs, func(context.Context) error {
+			return b.Holdout.validateControlArm(len(rewards))
+		})
+		if err != nil {
+			return res, err
+		}
+		res.Arm = b.Holdout.ControlArm
+		return res, nil
+	}
+
+	if len(b.Filters) > 0 {
+		var filtered []FilteredArm
+		err = b.stage(ctx, inst, StageFilter, func(ctx context.Context) (err error) {
+			rewards, filtered, err = filterArms(ctx, b.Filters, unit, banditContext, rewards)
+			return err
+		})
+		res.Filtered = filtered
+		if err != nil {
+			return res, err
+		}
+
+		res.Rewards = rewards
+	}
+
+	var probs []float64
+	err = b.stage(ctx, inst, StageProbs, func(ctx context.Context) (err error) {
+		if res.Holdout {
+			probs = uniformProbs(rewards)
+			return nil
+		}
+		probs, err = ComputeProbs(ctx, b.Strategy, banditContext, rewards)
+		return err
+	})
 	if err != nil {
 		return res, err
 	}
 
 	res.Probs = probs
 
-	result, err := b.Sample(probs, unit)
+	var arm int
+	err = b.stage(ctx, inst, StageSample, func(context.Context) (err error) {
+		arm, err = b.Sample(probs, unit)
+		return err
+	})
 	if err != nil {
 		return res, err
 	}
 
-	res.Arm = result
+	res.Arm = arm
 
 	return res, nil
 }
@@ -57,10 +166,62 @@ func (b *Bandit) SelectArm(ctx context.Context, unit string, banditContext inter
 // Result is the return type for a call to Bandit.SelectArm.
 // It will contain the reward estimates provided by the RewardSource, the computed arm selection probabilities,
 // and the index of the selected arm.
+// The DecisionID uniquely identifies the call to SelectArm that produced the Result.
+// Arms excluded by the Bandit's ArmFilters are listed in Filtered, and have Null reward estimates.
+// Forced is true if the arm was forced by an Overrider rather than selected by the bandit, and Holdout is true if the
+// unit was in the Bandit's Holdout.
+// A Result can be round-tripped through JSON, with the reward estimates encoded as tagged distributions.
 type Result struct {
-	Rewards []Dist
-	Probs   []float64
-	Arm     int
+	DecisionID string        `json:"decision_id,omitempty"`
+	Rewards    []Dist        `json:"rewards"`
+	Probs      []float64     `json:"probs"`
+	Arm        int           `json:"arm"`
+	Filtered   []FilteredArm `json:"filtered,omitempty"`
+	Forced     bool          `json:"forced,omitempty"`
+	Holdout    bool          `json:"holdout,omitempty"`
+}
+
+// Propensity returns the probability with which the selected arm was chosen.
+// It is zero if no arm was selected or if the arm was forced.
+func (r Result) Propensity() float64 {
+	if r.Arm < 0 || r.Arm >= len(r.Probs) {
+		return 0
+	}
+	return r.Probs[r.Arm]
+}
+
+// UnmarshalJSON decodes a JSON-encoded Result, using DistFromJSON to decode each of the reward estimates.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var v struct {
+		DecisionID string            `json:"decision_id"`
+		Rewards    []json.RawMessage `json:"rewards"`
+		Probs      []float64         `json:"probs"`
+		Arm        int               `json:"arm"`
+		Filtered   []FilteredArm     `json:"filtered"`
+		Forced     bool              `json:"forced"`
+		Holdout    bool              `json:"holdout"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	rewards := make([]Dist, len(v.Rewards))
+	for i := range v.Rewards {
+		dist, err := DistFromJSON(v.Rewards[i])
+		if err != nil {
+			return fmt.Errorf("arm %d: %w", i, err)
+		}
+		rewards[i] = dist
+	}
+
+	r.DecisionID = v.DecisionID
+	r.Rewards = rewards
+	r.Probs = v.Probs
+	r.Arm = v.Arm
+	r.Filtered = v.Filtered
+	r.Forced = v.Forced
+	r.Holdout = v.Holdout
+	return nil
 }
 
 // A Dist represents a one-dimensional probability distribution.
@@ -100,6 +261,40 @@ type Strategy interface {
 	ComputeProbs([]Dist) ([]float64, error)
 }
 
+// A ContextualStrategy is a Strategy whose probabilities also depend on the bandit context, for example because it
+// keeps state for each bandit context.
+// If a Bandit's Strategy implements ContextualStrategy, SelectArm calls ComputeContextualProbs instead of ComputeProbs.
+type ContextualStrategy interface {
+	Strategy
+	ComputeContextualProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error)
+}
+
+// ComputeProbs calls ComputeContextualProbs if strategy is a ContextualStrategy, and ComputeProbs otherwise.
+// Code that computes a Strategy's probabilities outside of SelectArm, such as a Strategy wrapper, should use it so that
+// contextual strategies get the bandit context.
+func ComputeProbs(ctx context.Context, strategy Strategy, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	if cs, ok := strategy.(ContextualStrategy); ok {
+		return cs.ComputeContextualProbs(ctx, banditContext, rewards)
+	}
+	return strategy.ComputeProbs(rewards)
+}
+
+// A Peeker is a Strategy with state that is updated when it computes probabilities, such as a Smoother, that can also
+// compute the probabilities it would return without updating the state.
+type Peeker interface {
+	PeekProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error)
+}
+
+// PeekProbs calls PeekProbs if strategy is a Peeker, and is the same as ComputeProbs otherwise.
+// Code that only reports a Strategy's probabilities, such as a read-only endpoint or an off-policy evaluation, should
+// use it so that it does not change the probabilities that live traffic gets.
+func PeekProbs(ctx context.Context, strategy Strategy, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	if p, ok := strategy.(Peeker); ok {
+		return p.PeekProbs(ctx, banditContext, rewards)
+	}
+	return ComputeProbs(ctx, strategy, banditContext, rewards)
+}
+
 // A Sampler returns a pseudo-random arm index given a set of probabilities and a string to hash.
 // Samplers should always return the same arm index for the same set of probabilities and unit value.
 type Sampler interface {
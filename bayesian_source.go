@@ -0,0 +1,141 @@
+package mab
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// NewBayesianSource returns a new BayesianSource for numArms arms, where every arm starts from the same prior.
+// Options can be used to set a different prior for individual arms or to customize how bandit contexts are keyed.
+// For example, for a click-through bandit with three arms and a flat prior:
+//	source := NewBayesianSource(3, BetaPrior(1, 1))
+func NewBayesianSource(numArms int, prior Prior, opts ...BayesianSourceOption) *BayesianSource {
+	s := &BayesianSource{
+		priors:     make([]Prior, numArms),
+		keyFunc:    comparableKey,
+		posteriors: make(map[interface{}][]Posterior),
+	}
+	for i := range s.priors {
+		s.priors[i] = prior
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// BayesianSource is an in-process RewardSource that maintains a conjugate posterior for each arm and bandit context.
+// Rewards are fed back with Observe, which implements Learner, and GetRewards returns the current posteriors.
+// Bandit contexts that have never been observed get the prior distributions.
+// A BayesianSource is safe for concurrent use.
+type BayesianSource struct {
+	priors  []Prior
+	keyFunc ContextKeyFunc
+
+	mu         sync.RWMutex
+	posteriors map[interface{}][]Posterior
+}
+
+// GetRewards returns the current posterior reward estimate for each arm, given the banditContext.
+// Returns an error if the banditContext cannot be used as a key, or if a prior has invalid parameters according to
+// ValidatePosterior.
+func (s *BayesianSource) GetRewards(ctx context.Context, banditContext interface{}) ([]Dist, error) {
+	key, err := s.keyFunc(banditContext)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	posteriors, ok := s.posteriors[key]
+	if !ok {
+		posteriors, err = s.newPosteriors()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rewards := make([]Dist, len(posteriors))
+	for i := range posteriors {
+		rewards[i] = posteriors[i].Dist()
+	}
+
+	return rewards, nil
+}
+
+// Observe updates the posterior for the given bandit context and arm with an observed reward.
+// The propensity is ignored, since the posterior of each arm only depends on that arm's rewards.
+// Returns an error if the arm index is out of range, if the banditContext cannot be used as a key, if a prior has
+// invalid parameters according to ValidatePosterior, or if the reward is not valid for the arm's posterior.
+func (s *BayesianSource) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	if arm < 0 || arm >= len(s.priors) {
+		return Errorf(ErrInvalidParameter, "arm index %d out of range [0, %d)", arm, len(s.priors))
+	}
+
+	key, err := s.keyFunc(banditContext)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	posteriors, ok := s.posteriors[key]
+	if !ok {
+		posteriors, err = s.newPosteriors()
+		if err != nil {
+			return err
+		}
+		s.posteriors[key] = posteriors
+	}
+
+	return posteriors[arm].Update(reward)
+}
+
+func (s *BayesianSource) newPosteriors() ([]Posterior, error) {
+	posteriors := make([]Posterior, len(s.priors))
+	for i := range s.priors {
+		posteriors[i] = s.priors[i]()
+		if err := ValidatePosterior(posteriors[i]); err != nil {
+			return nil, fmt.Errorf("invalid prior for arm %d: %w", i, err)
+		}
+	}
+	return posteriors, nil
+}
+
+// ContextKeyFunc converts a banditContext into a comparable value that can be used as a map key.
+type ContextKeyFunc func(banditContext interface{}) (interface{}, error)
+
+// BayesianSourceOption allows for optional arguments to NewBayesianSource
+type BayesianSourceOption func(source *BayesianSource)
+
+// WithArmPrior sets the prior for a single arm, overriding the prior given to NewBayesianSource.
+// Arm indices that are out of range are ignored.
+func WithArmPrior(arm int, prior Prior) BayesianSourceOption {
+	return func(source *BayesianSource) {
+		if arm >= 0 && arm < len(source.priors) {
+			source.priors[arm] = prior
+		}
+	}
+}
+
+// WithContextKey sets the function used to convert a banditContext into a key for the posteriors.
+// By default, the banditContext itself is used as the key, and it must be a comparable type such as a string or nil.
+func WithContextKey(f ContextKeyFunc) BayesianSourceOption {
+	return func(source *BayesianSource) {
+		source.keyFunc = f
+	}
+}
+
+func comparableKey(banditContext interface{}) (interface{}, error) {
+	if banditContext == nil {
+		return nil, nil
+	}
+	if !reflect.TypeOf(banditContext).Comparable() {
+		return nil, Errorf(ErrInvalidContext, "banditContext of type %T cannot be used as a key", banditContext)
+	}
+	return banditContext, nil
+}
@@ -0,0 +1,17 @@
+package mab
+
+import "time"
+
+// A Clock provides the current time.
+// Components that depend on elapsed time take a Clock so that time can be controlled in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is an adapter to allow a normal function to be used as a Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time { return f() }
+
+// SystemClock is a Clock that returns the current local time.
+var SystemClock Clock = ClockFunc(time.Now)
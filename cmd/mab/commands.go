@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/config"
+	"github.com/stitchfix/mab/sim"
+)
+
+type probsOutput struct {
+	Rewards []mab.Dist `json:"rewards"`
+	Probs   []float64  `json:"probs"`
+}
+
+func runProbs(args []string, env *env) error {
+	fs := newFlagSet("probs", "DIST...", env)
+	strategyFlags := addStrategyFlags(fs, config.ThompsonType)
+	in := fs.String("in", "", "read distributions from a JSON array in this file, or - for stdin")
+	format := addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	dists, err := readDists(*in, fs.Args(), env)
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFlags.build()
+	if err != nil {
+		return err
+	}
+
+	probs, err := strategy.ComputeProbs(dists)
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"arm", "reward", "mean", "prob"}}
+	for i, d := range dists {
+		t.add(strconv.Itoa(i), fmt.Sprint(d), formatFloat(d.Mean()), formatFloat(probs[i]))
+	}
+	return writeOutput(env.stdout, *format, probsOutput{dists, probs}, t)
+}
+
+type selection struct {
+	Unit string  `json:"unit"`
+	Arm  int     `json:"arm"`
+	Prob float64 `json:"prob"`
+}
+
+type selectOutput struct {
+	Probs      []float64   `json:"probs"`
+	Selections []selection `json:"selections"`
+}
+
+func runSelect(args []string, env *env) error {
+	fs := newFlagSet("select", "DIST...", env)
+	strategyFlags := addStrategyFlags(fs, config.ThompsonType)
+	in := fs.String("in", "", "read distributions from a JSON array in this file, or - for stdin")
+	unit := fs.String("unit", "", "the unit to select an arm for")
+	unitsPath := fs.String("units", "", "select an arm for each unit in this file, one per line, or - for stdin")
+	format := addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if (*unit == "") == (*unitsPath == "") {
+		return usagef("exactly one of -unit or -units must be set")
+	}
+	if *in == "-" && *unitsPath == "-" {
+		return usagef("-in and -units cannot both read from stdin")
+	}
+
+	dists, err := readDists(*in, fs.Args(), env)
+	if err != nil {
+		return err
+	}
+	strategy, err := strategyFlags.build()
+	if err != nil {
+		return err
+	}
+
+	units := []string{*unit}
+	if *unitsPath != "" {
+		units, err = readUnits(*unitsPath, env)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the probabilities don't depend on the unit, so they are computed once, rather than by Bandit.SelectArm for
+	// each unit
+	probs, err := strategy.ComputeProbs(dists)
+	if err != nil {
+		return err
+	}
+
+	sampler := mab.NewSha1Sampler()
+	out := selectOutput{Probs: probs, Selections: make([]selection, len(units))}
+	t := table{header: []string{"unit", "arm", "prob"}}
+	for i, u := range units {
+		arm, err := sampler.Sample(probs, u)
+		if err != nil {
+			return err
+		}
+		out.Selections[i] = selection{u, arm, probs[arm]}
+		t.add(u, strconv.Itoa(arm), formatFloat(probs[arm]))
+	}
+	return writeOutput(env.stdout, *format, out, t)
+}
+
+// readUnits reads one unit per line from the file at path, or stdin if path is "-". Blank lines are skipped.
+func readUnits(path string, env *env) ([]string, error) {
+	data, err := readInput(path, env)
+	if err != nil {
+		return nil, err
+	}
+	var units []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if u := strings.TrimSpace(scanner.Text()); u != "" {
+			units = append(units, u)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(units) == 0 {
+		return nil, fmt.Errorf("%s: no units", path)
+	}
+	return units, nil
+}
+
+func runSimulate(args []string, env *env) error {
+	fs := newFlagSet("simulate", "-means 0.1,0.2,...", env)
+	strategyFlags := addStrategyFlags(fs, config.ThompsonType)
+	meansFlag := fs.String("means", "", "comma-separated true mean reward of each arm")
+	environment := fs.String("env", "bernoulli", "reward environment: bernoulli, with a beta(1,1) prior, or gaussian, with a normal(0,10) prior")
+	noise := fs.Float64("noise", 1, "standard deviation of gaussian rewards")
+	rounds := fs.Int("rounds", 1000, "number of rounds in each run")
+	runs := fs.Int("runs", 10, "number of runs")
+	seed := fs.Int64("seed", 1, "seed of the first run")
+	format := addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected arguments %v", fs.Args())
+	}
+
+	means, err := parseMeans(*meansFlag)
+	if err != nil {
+		return err
+	}
+
+	var simEnv sim.Environment
+	var prior mab.Prior
+	switch *environment {
+	case "bernoulli":
+		for i, m := range means {
+			if m < 0 || m > 1 {
+				return usagef("bernoulli mean of arm %d must be between 0 and 1. got=%f", i, m)
+			}
+		}
+		simEnv, prior = sim.Bernoulli{Means: means}, mab.BetaPrior(1, 1)
+	case "gaussian":
+		if *noise <= 0 {
+			return usagef("noise must be > 0. got=%f", *noise)
+		}
+		simEnv, prior = sim.Gaussian{Means: means, Sigma: *noise}, mab.NormalPrior(0, 10, *noise)
+	default:
+		return usagef("unknown environment %q. must be bernoulli or gaussian", *environment)
+	}
+
+	// build once to report invalid strategy flags before running
+	if _, err := strategyFlags.build(); err != nil {
+		return err
+	}
+
+	s := sim.Simulation{
+		Environment: simEnv,
+		NewBandit: func() *mab.Bandit {
+			strategy, _ := strategyFlags.build()
+			return &mab.Bandit{
+				RewardSource: mab.NewBayesianSource(len(means), prior),
+				Strategy:     strategy,
+				Sampler:      mab.NewSha1Sampler(),
+			}
+		},
+		Rounds: *rounds,
+		Runs:   *runs,
+		Seed:   *seed,
+	}
+
+	report, err := s.Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"arm", "mean", "mean_pulls"}}
+	for i, m := range means {
+		t.add(strconv.Itoa(i), formatFloat(m), formatFloat(report.MeanArmPulls[i]))
+	}
+	t.footer = []string{
+		"",
+		"final regret:  " + formatFloat(report.FinalRegret()),
+		"best arm rate: " + formatFloat(report.BestArmRate),
+	}
+	return writeOutput(env.stdout, *format, report, t)
+}
+
+func parseMeans(s string) ([]float64, error) {
+	if s == "" {
+		return nil, usagef("-means must be set")
+	}
+	fields := strings.Split(s, ",")
+	means := make([]float64, len(fields))
+	for i, field := range fields {
+		m, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, usagef("invalid mean %q", field)
+		}
+		means[i] = m
+	}
+	return means, nil
+}
+
+type integrateOutput struct {
+	Dist  mab.Dist `json:"dist"`
+	Fn    string   `json:"fn"`
+	A     float64  `json:"a"`
+	B     float64  `json:"b"`
+	Value float64  `json:"value"`
+}
+
+// integrands are the functions of a distribution that can be integrated.
+var integrands = map[string]func(d mab.Dist) func(float64) float64{
+	"pdf":  func(d mab.Dist) func(float64) float64 { return d.Prob },
+	"cdf":  func(d mab.Dist) func(float64) float64 { return d.CDF },
+	"mean": func(d mab.Dist) func(float64) float64 { return func(x float64) float64 { return x * d.Prob(x) } },
+}
+
+func runIntegrate(args []string, env *env) error {
+	fs := newFlagSet("integrate", "DIST", env)
+	quadrature := addQuadratureFlags(fs)
+	fn := fs.String("fn", "pdf", "function to integrate: pdf, cdf or mean, which is x*pdf(x)")
+	a := fs.Float64("a", 0, "lower limit of integration (default the lower end of the distribution's support)")
+	b := fs.Float64("b", 0, "upper limit of integration (default the upper end of the distribution's support)")
+	format := addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("expected one distribution. got=%d", fs.NArg())
+	}
+
+	integrand, ok := integrands[*fn]
+	if !ok {
+		return usagef("unknown function %q. must be pdf, cdf or mean", *fn)
+	}
+
+	dist, err := parseDist(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	lower, upper := dist.Support()
+	if isSet(fs, "a") {
+		lower = *a
+	}
+	if isSet(fs, "b") {
+		upper = *b
+	}
+	if !(lower < upper) {
+		return fmt.Errorf("integration interval [%s, %s] is empty", formatFloat(lower), formatFloat(upper))
+	}
+
+	q, err := quadrature.build()
+	if err != nil {
+		return err
+	}
+
+	value, err := q.Integrate(integrand(dist), lower, upper)
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"dist", "fn", "a", "b", "value"}}
+	t.add(fmt.Sprint(dist), *fn, formatFloat(lower), formatFloat(upper), formatFloat(value))
+	return writeOutput(env.stdout, *format, integrateOutput{dist, *fn, lower, upper, value}, t)
+}
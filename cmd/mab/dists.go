@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/stitchfix/mab"
+)
+
+// distParams are the parameter names of each built-in distribution, in the order they are written in an expression.
+var distParams = map[string][]string{
+	mab.BetaType:        {"alpha", "beta"},
+	mab.NormalType:      {"mu", "sigma"},
+	mab.LogitNormalType: {"mu", "sigma"},
+	mab.PointType:       {"mu"},
+	mab.NullType:        {},
+}
+
+// parseDist parses a distribution expression such as "beta(10,20)" or "null", or the tagged JSON encoding of a
+// distribution. Expression names are case-insensitive, and "logitnormal" is accepted for "logit_normal", so that
+// the output of a Dist's String method can be parsed.
+func parseDist(expr string) (mab.Dist, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") {
+		return mab.DistFromJSON([]byte(expr))
+	}
+
+	name, args := expr, ""
+	if open := strings.Index(expr, "("); open >= 0 {
+		if !strings.HasSuffix(expr, ")") {
+			return nil, fmt.Errorf("invalid distribution %q: missing closing parenthesis", expr)
+		}
+		name, args = expr[:open], expr[open+1:len(expr)-1]
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "logitnormal" {
+		name = mab.LogitNormalType
+	}
+	params, ok := distParams[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid distribution %q: unknown type %q", expr, name)
+	}
+
+	var values []string
+	if strings.TrimSpace(args) != "" {
+		values = strings.Split(args, ",")
+	}
+	if len(values) != len(params) {
+		return nil, fmt.Errorf("invalid distribution %q: %s takes %d parameters. got=%d", expr, name, len(params), len(values))
+	}
+
+	fields := map[string]interface{}{"type": name}
+	for i, param := range params {
+		v, err := strconv.ParseFloat(strings.TrimSpace(values[i]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid distribution %q: %s is not a number", expr, param)
+		}
+		fields[param] = v
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	dist, err := mab.DistFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid distribution %q: %w", expr, err)
+	}
+	return dist, nil
+}
+
+// readDists returns the distributions read from the JSON file at path, if path is not empty, followed by the
+// distributions parsed from exprs.
+func readDists(path string, exprs []string, env *env) ([]mab.Dist, error) {
+	var dists []mab.Dist
+
+	if path != "" {
+		data, err := readInput(path, env)
+		if err != nil {
+			return nil, err
+		}
+		dists, err = mab.DistsFromJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	for _, expr := range exprs {
+		dist, err := parseDist(expr)
+		if err != nil {
+			return nil, err
+		}
+		dists = append(dists, dist)
+	}
+
+	if len(dists) == 0 {
+		return nil, usagef("no distributions given")
+	}
+	return dists, nil
+}
+
+// readInput reads all of the file at path, or stdin if path is "-".
+func readInput(path string, env *env) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(env.stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/config"
+	"github.com/stitchfix/mab/numint"
+)
+
+// newFlagSet returns a FlagSet for a command that reports errors instead of exiting, and prints its usage to stderr.
+func newFlagSet(name, args string, env *env) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "usage: mab %s [flags] %s\n\nflags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the command's flags. The flag package's own messages are suppressed, so that run reports
+// errors in the same way for every command, and the usage is printed once for -h.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	out, usage := fs.Output(), fs.Usage
+	fs.SetOutput(ioutil.Discard)
+	fs.Usage = func() {}
+	err := fs.Parse(args)
+	fs.SetOutput(out)
+	fs.Usage = usage
+
+	if err == flag.ErrHelp {
+		fs.Usage()
+		return err
+	}
+	if err != nil {
+		return usageError{err.Error()}
+	}
+	return nil
+}
+
+// isSet reports whether the named flag was set on the command line.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// quadratureFlags configure the numint.Quadrature used by the thompson strategy and the integrate command.
+// Only the flags that are set are passed to config.BuildQuadrature, so the defaults are the same as numint's.
+type quadratureFlags struct {
+	fs           *flag.FlagSet
+	rule         string
+	degree       int
+	subintervals int
+	maxIter      int
+	absTol       float64
+	relTol       float64
+}
+
+func addQuadratureFlags(fs *flag.FlagSet) *quadratureFlags {
+	q := &quadratureFlags{fs: fs}
+	fs.StringVar(&q.rule, "rule", "gauss_legendre", "quadrature rule: gauss_legendre, newton_cotes_open or newton_cotes_closed")
+	fs.IntVar(&q.degree, "degree", 0, "degree of the quadrature rule")
+	fs.IntVar(&q.subintervals, "subintervals", 0, "number of subintervals the integration interval is initially divided into")
+	fs.IntVar(&q.maxIter, "max-iter", 0, "maximum number of times the subintervals are halved")
+	fs.Float64Var(&q.absTol, "abs-tol", 0, "absolute tolerance for convergence")
+	fs.Float64Var(&q.relTol, "rel-tol", 0, "relative tolerance for convergence")
+	return q
+}
+
+// params returns the quadrature parameters that were set, keyed as in the config package.
+func (q *quadratureFlags) params() map[string]interface{} {
+	params := make(map[string]interface{})
+	set := func(flagName, param string, v interface{}) {
+		if isSet(q.fs, flagName) {
+			params[param] = v
+		}
+	}
+	set("rule", "rule", q.rule)
+	set("degree", "degree", q.degree)
+	set("subintervals", "subintervals", q.subintervals)
+	set("max-iter", "max_iter", q.maxIter)
+	set("abs-tol", "abs_tol", q.absTol)
+	set("rel-tol", "rel_tol", q.relTol)
+	return params
+}
+
+func (q *quadratureFlags) build() (*numint.Quadrature, error) {
+	c, err := config.NewComponent("", q.params())
+	if err != nil {
+		return nil, err
+	}
+	quad, err := config.BuildQuadrature(c)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+	return quad, nil
+}
+
+// strategyFlags choose and configure the Strategy.
+type strategyFlags struct {
+	strategy   string
+	epsilon    float64
+	alpha      float64
+	iterations int
+	quadrature *quadratureFlags
+}
+
+func addStrategyFlags(fs *flag.FlagSet, defaultStrategy string) *strategyFlags {
+	s := &strategyFlags{}
+	fs.StringVar(&s.strategy, "strategy", defaultStrategy, "strategy: thompson, thompson_mc, epsilon_greedy, proportional or ucb")
+	fs.Float64Var(&s.epsilon, "epsilon", 0.1, "exploration probability for epsilon_greedy")
+	fs.Float64Var(&s.alpha, "alpha", 1, "width of the confidence bound for ucb")
+	fs.IntVar(&s.iterations, "iterations", 1000, "number of Monte Carlo samples for thompson_mc")
+	s.quadrature = addQuadratureFlags(fs)
+	return s
+}
+
+// build builds the Strategy with config.Builder, so the strategy types and parameter checks are the same as for
+// configuration files.
+func (s *strategyFlags) build() (mab.Strategy, error) {
+	params := make(map[string]interface{})
+	switch s.strategy {
+	case config.ThompsonType:
+		params["quadrature"] = s.quadrature.params()
+	case config.ThompsonMCType:
+		params["iterations"] = s.iterations
+	case config.EpsilonGreedyType:
+		params["epsilon"] = s.epsilon
+	case config.UCBType:
+		params["alpha"] = s.alpha
+	}
+
+	c, err := config.NewComponent(s.strategy, params)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := config.NewBuilder().BuildStrategy(c)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+	return strategy, nil
+}
+
+// addOutputFlag adds the -o flag for choosing between table and JSON output.
+func addOutputFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", "table", "output format: table or json")
+}
@@ -0,0 +1,108 @@
+// Command mab answers "what would this bandit do?" from the command line.
+//
+// Usage:
+//	mab probs [flags] DIST...                 compute arm selection probabilities
+//	mab select [flags] -unit UNIT DIST...     select an arm for a unit, or for each unit in a file with -units
+//	mab simulate [flags] -means 0.1,0.2,...   simulate a strategy against arms with known mean rewards
+//	mab integrate [flags] DIST                integrate the pdf, cdf or mean of a distribution with numint
+//
+// Distributions are written as they are printed by their String methods, such as "beta(10,20)", "normal(0.5,0.1)",
+// "logitnormal(-2,0.3)", "point(0.5)" and "null", or as tagged JSON such as '{"type": "beta", "alpha": 10, "beta": 20}'.
+// They can also be read from a JSON array of tagged distributions with -in, where "-" reads from stdin.
+//
+// The strategy is chosen with -strategy, which accepts any strategy type known to the config package, and its
+// parameters with -epsilon, -alpha, -iterations and the quadrature flags. Output is a table by default, or JSON
+// with -o json. Run "mab <command> -h" for the flags of each command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// A command is a subcommand of mab.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, env *env) error
+}
+
+// env holds the standard streams, so that commands can be run in tests.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var commands = []command{
+	{"probs", "compute arm selection probabilities", runProbs},
+	{"select", "select an arm for one or more units", runSelect},
+	{"simulate", "simulate a strategy against known mean rewards", runSimulate},
+	{"integrate", "integrate a function of a distribution with numint", runIntegrate},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{os.Stdin, os.Stdout, os.Stderr}))
+}
+
+// run runs the command named by args[0] and returns the exit code: 0 for success, 1 for errors, and 2 for usage errors.
+func run(args []string, env *env) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(env.stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:], env)
+		switch {
+		case err == flag.ErrHelp:
+			return 0
+		case isUsageError(err):
+			fmt.Fprintf(env.stderr, "mab %s: %v\n", cmd.name, err)
+			return 2
+		case err != nil:
+			fmt.Fprintf(env.stderr, "mab %s: %v\n", cmd.name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(env.stderr, "mab: unknown command %q\n", args[0])
+	usage(env.stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: mab <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `run "mab <command> -h" for the flags of a command`)
+}
+
+// usageError is an error in the command line, as opposed to an error computing the result.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...interface{}) error {
+	return usageError{fmt.Sprintf(format, args...)}
+}
+
+func isUsageError(err error) bool {
+	_, ok := err.(usageError)
+	return ok
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runMab runs the mab command with args and stdin, and returns its exit code and output.
+func runMab(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &env{strings.NewReader(stdin), &stdout, &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestParseDist(t *testing.T) {
+	tests := map[string]string{
+		"beta(10, 20)":              "Beta(10.000000,20.000000)",
+		"Beta(10.000000,20.000000)": "Beta(10.000000,20.000000)",
+		"normal(0.5,0.1)":           "Normal(0.500000,0.100000)",
+		"LogitNormal(-2,0.3)":       "LogitNormal(-2.000000,0.300000)",
+		"point(1)":                  "Point(1.000000)",
+		"null":                      "Null()",
+		"Null()":                    "Null()",
+		`{"type": "beta", "alpha": 1, "beta": 2}`: "Beta(1.000000,2.000000)",
+	}
+	for expr, expected := range tests {
+		d, err := parseDist(expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if s := d.(interface{ String() string }).String(); s != expected {
+			t.Errorf("%s: expected %s. got=%s", expr, expected, s)
+		}
+	}
+
+	for _, expr := range []string{"gamma(1,2)", "beta(1)", "beta(1,x)", "beta(1,2", "beta(-1,2)"} {
+		if _, err := parseDist(expr); err == nil {
+			t.Errorf("%s: expected error but didn't get one", expr)
+		}
+	}
+}
+
+func TestProbs(t *testing.T) {
+	code, stdout, stderr := runMab("", "probs", "-strategy", "epsilon_greedy", "-epsilon", "0.2", "-o", "json", "point(1)", "point(2)", "null")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+
+	var out struct {
+		Rewards []json.RawMessage `json:"rewards"`
+		Probs   []float64         `json:"probs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{0.1, 0.9, 0}
+	for i := range expected {
+		if math.Abs(out.Probs[i]-expected[i]) > 1e-9 {
+			t.Errorf("expected probs %v. got=%v", expected, out.Probs)
+			break
+		}
+	}
+	if len(out.Rewards) != 3 || !strings.Contains(string(out.Rewards[2]), `"null"`) {
+		t.Errorf("unexpected rewards: %s", stdout)
+	}
+
+	code, stdout, _ = runMab(`[{"type": "beta", "alpha": 10, "beta": 20}, {"type": "beta", "alpha": 20, "beta": 10}]`, "probs", "-in", "-", "-degree", "8")
+	if code != 0 || !strings.HasPrefix(stdout, "arm") || len(strings.Split(strings.TrimSpace(stdout), "\n")) != 3 {
+		t.Errorf("unexpected table output: %q", stdout)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	units := filepath.Join(dir, "units.txt")
+	if err := ioutil.WriteFile(units, []byte("a\nb\n\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr := runMab("", "select", "-units", units, "-strategy", "proportional", "-o", "json", "point(1)", "point(3)")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var out selectOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Selections) != 3 || out.Selections[2].Unit != "c" {
+		t.Errorf("unexpected selections: %+v", out.Selections)
+	}
+
+	// selection is deterministic for a unit
+	_, first, _ := runMab("", "select", "-unit", "b", "-strategy", "proportional", "point(1)", "point(3)")
+	_, second, _ := runMab("", "select", "-unit", "b", "-strategy", "proportional", "point(1)", "point(3)")
+	if first != second {
+		t.Errorf("expected the same output for the same unit. got %q and %q", first, second)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	code, stdout, stderr := runMab("", "simulate", "-means", "0.1,0.9", "-rounds", "200", "-runs", "3", "-strategy", "epsilon_greedy")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "final regret:") || !strings.Contains(stdout, "best arm rate: 1") {
+		t.Errorf("unexpected output: %s", stdout)
+	}
+}
+
+func TestIntegrate(t *testing.T) {
+	code, stdout, stderr := runMab("", "integrate", "-fn", "mean", "-o", "json", "beta(2,3)")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var out struct {
+		Value float64 `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(out.Value-0.4) > 1e-6 {
+		t.Errorf("expected 0.4. got=%f", out.Value)
+	}
+
+	code, stdout, _ = runMab("", "integrate", "-a", "-10", "-b", "0", "normal(0,1)")
+	if code != 0 || !strings.Contains(stdout, "0.5\n") {
+		t.Errorf("expected half the normal's mass below 0. got=%q", stdout)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		code int
+	}{
+		"no command":        {nil, 2},
+		"unknown command":   {[]string{"explain"}, 2},
+		"unknown flag":      {[]string{"probs", "-bogus", "beta(1,1)"}, 2},
+		"no dists":          {[]string{"probs"}, 2},
+		"bad strategy":      {[]string{"probs", "-strategy", "magic", "beta(1,1)"}, 2},
+		"bad epsilon":       {[]string{"probs", "-strategy", "epsilon_greedy", "-epsilon", "2", "beta(1,1)"}, 2},
+		"bad format":        {[]string{"probs", "-o", "xml", "beta(1,1)"}, 2},
+		"bad dist":          {[]string{"probs", "gamma(1,1)"}, 1},
+		"missing unit":      {[]string{"select", "beta(1,1)"}, 2},
+		"missing means":     {[]string{"simulate"}, 2},
+		"bernoulli range":   {[]string{"simulate", "-means", "0.5,2"}, 2},
+		"empty interval":    {[]string{"integrate", "point(1)"}, 1},
+		"unknown integrand": {[]string{"integrate", "-fn", "var", "beta(1,1)"}, 2},
+		"help":              {[]string{"probs", "-h"}, 0},
+	}
+	for name, test := range tests {
+		if code, _, _ := runMab("", test.args...); code != test.code {
+			t.Errorf("%s: expected exit code %d. got=%d", name, test.code, code)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/numint"
+)
+
+// Build builds a Bandit from cfg with the default Builder.
+func Build(cfg Config) (*mab.Bandit, error) {
+	return NewBuilder().Build(cfg)
+}
+
+// NewBuilder returns a new Builder with any Option arguments applied.
+func NewBuilder(opts ...Option) *Builder {
+	b := &Builder{}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// A Builder builds Bandits from configuration, using the registered factory for each component type.
+type Builder struct {
+	client mab.HttpDoer
+}
+
+// Option is a function that can be passed to NewBuilder to override the default settings.
+type Option func(*Builder)
+
+// WithHTTPClient sets the HttpDoer used by HTTP reward sources. If it is set, the timeout parameter of HTTP reward
+// sources is ignored. By default, each HTTP reward source gets its own http.Client with the configured timeout.
+func WithHTTPClient(client mab.HttpDoer) Option {
+	return func(b *Builder) {
+		b.client = client
+	}
+}
+
+// HTTPClient returns the HttpDoer set with WithHTTPClient, or nil if none was set.
+func (b *Builder) HTTPClient() mab.HttpDoer {
+	return b.client
+}
+
+// Build builds a Bandit from cfg. The Bandit's Version is set to Version(cfg).
+// Returns a *FieldError naming the offending field if a component type is unknown or a parameter is invalid.
+func (b *Builder) Build(cfg Config) (*mab.Bandit, error) {
+	source, err := b.buildRewardSource(cfg.RewardSource)
+	if err != nil {
+		return nil, inField("reward_source", err)
+	}
+
+	strategy, err := b.BuildStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, inField("strategy", err)
+	}
+
+	sampler, err := b.buildSampler(cfg.Sampler)
+	if err != nil {
+		return nil, inField("sampler", err)
+	}
+
+	return &mab.Bandit{
+		RewardSource: source,
+		Strategy:     strategy,
+		Sampler:      sampler,
+		Version:      Version(cfg),
+	}, nil
+}
+
+// BuildAll builds a Bandit for each named configuration, such as those returned by LoadBandits, and sets its Name.
+// Returns a *FieldError for the first invalid bandit in name order, with a field such as "bandits.homepage.strategy".
+func (b *Builder) BuildAll(cfgs map[string]Config) (map[string]*mab.Bandit, error) {
+	names := make([]string, 0, len(cfgs))
+	for name := range cfgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bandits := make(map[string]*mab.Bandit, len(cfgs))
+	for _, name := range names {
+		bandit, err := b.Build(cfgs[name])
+		if err != nil {
+			return nil, inField("bandits."+name, err)
+		}
+		bandit.Name = name
+		bandits[name] = bandit
+	}
+	return bandits, nil
+}
+
+func (b *Builder) buildRewardSource(c Component) (mab.RewardSource, error) {
+	if c.Type == "" {
+		return nil, FieldErrorf("type", "must be set")
+	}
+	factory, ok := lookupRewardSource(c.Type)
+	if !ok {
+		return nil, FieldErrorf("type", "unknown reward source type %q", c.Type)
+	}
+	return factory(c, b)
+}
+
+// BuildStrategy builds a Strategy from its component configuration, such as the strategy of a Config.
+// Returns a *FieldError naming the offending field if the type is unknown or a parameter is invalid.
+func (b *Builder) BuildStrategy(c Component) (mab.Strategy, error) {
+	if c.Type == "" {
+		return nil, FieldErrorf("type", "must be set")
+	}
+	factory, ok := lookupStrategy(c.Type)
+	if !ok {
+		return nil, FieldErrorf("type", "unknown strategy type %q", c.Type)
+	}
+	return factory(c, b)
+}
+
+func (b *Builder) buildSampler(c Component) (mab.Sampler, error) {
+	if c.Type == "" {
+		c.Type = Sha1Type
+	}
+	factory, ok := lookupSampler(c.Type)
+	if !ok {
+		return nil, FieldErrorf("type", "unknown sampler type %q", c.Type)
+	}
+	return factory(c, b)
+}
+
+type httpParams struct {
+	URL     string `json:"url"`
+	Parser  string `json:"parser"`
+	Timeout string `json:"timeout"`
+}
+
+func buildHTTPSource(c Component, b *Builder) (mab.RewardSource, error) {
+	var p httpParams
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.URL == "" {
+		return nil, FieldErrorf("url", "must be set")
+	}
+	if p.Parser == "" {
+		return nil, FieldErrorf("parser", "must be set")
+	}
+	parser, ok := lookupParser(p.Parser)
+	if !ok {
+		return nil, FieldErrorf("parser", "unknown parser %q", p.Parser)
+	}
+	var timeout time.Duration
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil {
+			return nil, FieldErrorf("timeout", "must be a duration such as \"100ms\". got=%q", p.Timeout)
+		}
+		if d < 0 {
+			return nil, FieldErrorf("timeout", "must be >= 0. got=%s", d)
+		}
+		timeout = d
+	}
+
+	client := b.client
+	if client == nil {
+		client = &http.Client{Timeout: timeout}
+	}
+
+	return mab.NewHTTPSource(client, p.URL, parser), nil
+}
+
+func buildStub(c Component, b *Builder) (mab.RewardSource, error) {
+	var p struct {
+		Rewards json.RawMessage `json:"rewards"`
+	}
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	if len(p.Rewards) == 0 {
+		return nil, FieldErrorf("rewards", "must be set")
+	}
+	rewards, err := mab.DistsFromJSON(p.Rewards)
+	if err != nil {
+		return nil, inField("rewards", err)
+	}
+	return &mab.RewardStub{Rewards: rewards}, nil
+}
+
+type quadratureParams struct {
+	Rule         string   `json:"rule"`
+	Degree       int      `json:"degree"`
+	Subintervals int      `json:"subintervals"`
+	MaxIter      int      `json:"max_iter"`
+	AbsTol       *float64 `json:"abs_tol"`
+	RelTol       *float64 `json:"rel_tol"`
+}
+
+// ruleDegrees is the range of degrees implemented for each quadrature rule.
+var ruleDegrees = map[string]struct{ min, max int }{
+	"gauss_legendre":      {1, 12},
+	"newton_cotes_open":   {2, 7},
+	"newton_cotes_closed": {1, 5},
+}
+
+func (p quadratureParams) options() ([]numint.Option, error) {
+	var opts []numint.Option
+
+	if p.Rule != "" || p.Degree != 0 {
+		rule := p.Rule
+		if rule == "" {
+			rule = "gauss_legendre"
+		}
+		degrees, ok := ruleDegrees[rule]
+		if !ok {
+			return nil, FieldErrorf("rule", "unknown rule %q", rule)
+		}
+		if p.Degree < degrees.min || p.Degree > degrees.max {
+			return nil, FieldErrorf("degree", "must be between %d and %d for %s. got=%d", degrees.min, degrees.max, rule, p.Degree)
+		}
+		switch rule {
+		case "gauss_legendre":
+			opts = append(opts, numint.WithRule(numint.GaussLegendre(p.Degree)))
+		case "newton_cotes_open":
+			r := numint.NewtonCotesOpen(p.Degree)
+			opts = append(opts, numint.WithRule(&r))
+		case "newton_cotes_closed":
+			r := numint.NewtonCotesClosed(p.Degree)
+			opts = append(opts, numint.WithRule(&r))
+		}
+	}
+
+	if p.Subintervals < 0 {
+		return nil, FieldErrorf("subintervals", "must be > 0. got=%d", p.Subintervals)
+	}
+	if p.Subintervals > 0 {
+		opts = append(opts, numint.WithSubDivider(numint.EquallySpaced(p.Subintervals)))
+	}
+
+	if p.MaxIter < 0 {
+		return nil, FieldErrorf("max_iter", "must be > 0. got=%d", p.MaxIter)
+	}
+	if p.MaxIter > 0 {
+		opts = append(opts, numint.WithMaxIter(p.MaxIter))
+	}
+
+	if p.AbsTol != nil && *p.AbsTol <= 0 {
+		return nil, FieldErrorf("abs_tol", "must be > 0. got=%f", *p.AbsTol)
+	}
+	if p.RelTol != nil && *p.RelTol <= 0 {
+		return nil, FieldErrorf("rel_tol", "must be > 0. got=%f", *p.RelTol)
+	}
+	switch {
+	case p.AbsTol != nil && p.RelTol != nil:
+		opts = append(opts, numint.WithAbsAndRelTol(*p.AbsTol, *p.RelTol))
+	case p.AbsTol != nil:
+		opts = append(opts, numint.WithAbsTol(*p.AbsTol))
+	case p.RelTol != nil:
+		opts = append(opts, numint.WithRelTol(*p.RelTol))
+	}
+
+	return opts, nil
+}
+
+// BuildQuadrature builds a numint.Quadrature from c, which has the same parameters as the quadrature of a thompson
+// strategy, such as {"rule": "gauss_legendre", "degree": 8}. The component's Type is ignored.
+// Returns a *FieldError naming the offending field if a parameter is invalid.
+func BuildQuadrature(c Component) (*numint.Quadrature, error) {
+	var p quadratureParams
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	opts, err := p.options()
+	if err != nil {
+		return nil, err
+	}
+	return numint.NewQuadrature(opts...), nil
+}
+
+func buildThompson(c Component, b *Builder) (mab.Strategy, error) {
+	var p struct {
+		Quadrature quadratureParams `json:"quadrature"`
+	}
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	opts, err := p.Quadrature.options()
+	if err != nil {
+		return nil, inField("quadrature", err)
+	}
+	return mab.NewThompson(numint.NewQuadrature(opts...)), nil
+}
+
+func buildThompsonMC(c Component, b *Builder) (mab.Strategy, error) {
+	var p struct {
+		Iterations int `json:"iterations"`
+	}
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.Iterations <= 0 {
+		return nil, FieldErrorf("iterations", "must be > 0. got=%d", p.Iterations)
+	}
+	return mab.NewThompsonMC(p.Iterations), nil
+}
+
+func buildEpsilonGreedy(c Component, b *Builder) (mab.Strategy, error) {
+	var p struct {
+		Epsilon *float64 `json:"epsilon"`
+	}
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.Epsilon == nil {
+		return nil, FieldErrorf("epsilon", "must be set")
+	}
+	if *p.Epsilon < 0 || *p.Epsilon > 1 {
+		return nil, FieldErrorf("epsilon", "must be between 0 and 1. got=%f", *p.Epsilon)
+	}
+	return mab.NewEpsilonGreedy(*p.Epsilon), nil
+}
+
+func buildProportional(c Component, b *Builder) (mab.Strategy, error) {
+	if err := c.Decode(&struct{}{}); err != nil {
+		return nil, err
+	}
+	return mab.NewProportional(), nil
+}
+
+func buildUCB(c Component, b *Builder) (mab.Strategy, error) {
+	var p struct {
+		Alpha *float64 `json:"alpha"`
+	}
+	if err := c.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.Alpha == nil {
+		return nil, FieldErrorf("alpha", "must be set")
+	}
+	if *p.Alpha < 0 {
+		return nil, FieldErrorf("alpha", "must be >= 0. got=%f", *p.Alpha)
+	}
+	return mab.NewUCB(*p.Alpha), nil
+}
+
+func buildSha1Sampler(c Component, b *Builder) (mab.Sampler, error) {
+	if err := c.Decode(&struct{}{}); err != nil {
+		return nil, err
+	}
+	return mab.NewSha1Sampler(), nil
+}
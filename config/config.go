@@ -0,0 +1,255 @@
+// Package config builds Bandits from declarative JSON or YAML configuration.
+package config
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config describes how to assemble a Bandit. For example, in YAML:
+//	reward_source:
+//	  type: http
+//	  url: http://localhost:1337/rewards
+//	  parser: beta
+//	  timeout: 100ms
+//	strategy:
+//	  type: thompson
+//	  quadrature:
+//	    rule: gauss_legendre
+//	    degree: 4
+//	    abs_tol: 0.00001
+//	sampler:
+//	  type: sha1
+// The sampler can be omitted, in which case the SHA1 sampler is used.
+type Config struct {
+	RewardSource Component `json:"reward_source"`
+	Strategy     Component `json:"strategy"`
+	Sampler      Component `json:"sampler,omitempty"`
+}
+
+// A Component configures a single RewardSource, Strategy or Sampler.
+// Type selects the factory that builds the component, and the other keys are the component's parameters.
+type Component struct {
+	Type   string
+	params map[string]json.RawMessage
+}
+
+// NewComponent returns a Component of the given type, with parameters taken from the JSON encoding of params,
+// which may be nil.
+func NewComponent(typeName string, params interface{}) (Component, error) {
+	c := Component{Type: typeName}
+	if params == nil {
+		return c, nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.params); err != nil {
+		return c, fmt.Errorf("params must encode to a JSON object: %w", err)
+	}
+	delete(c.params, "type")
+	return c, nil
+}
+
+// UnmarshalJSON decodes a component from a JSON object. A missing "type" key is reported when the Bandit is built.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var typeName string
+	if typeData, ok := raw["type"]; ok {
+		if err := json.Unmarshal(typeData, &typeName); err != nil {
+			return fmt.Errorf("type must be a string: %w", err)
+		}
+		delete(raw, "type")
+	}
+	c.Type = typeName
+	c.params = raw
+	return nil
+}
+
+// MarshalJSON encodes the component as a JSON object with a "type" key.
+func (c Component) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(c.params)+1)
+	for k, v := range c.params {
+		obj[k] = v
+	}
+	obj["type"] = c.Type
+	return json.Marshal(obj)
+}
+
+// Decode decodes the component's parameters into v, which is usually a pointer to a struct with json tags.
+// Unknown parameters are an error, so that misspelled keys are not silently ignored.
+func (c Component) Decode(v interface{}) error {
+	data, err := json.Marshal(c.params)
+	if err != nil {
+		return err
+	}
+	if c.params == nil {
+		data = []byte("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// Params returns the names of the component's parameters in sorted order.
+func (c Component) Params() []string {
+	names := make([]string, 0, len(c.params))
+	for name := range c.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeError converts errors from encoding/json into FieldErrors where the field can be determined.
+func decodeError(err error) error {
+	switch e := err.(type) {
+	case *json.UnmarshalTypeError:
+		if e.Field != "" {
+			return &FieldError{Field: e.Field, Err: fmt.Errorf("cannot use %s as %s", e.Value, e.Type)}
+		}
+	default:
+		msg := err.Error()
+		const prefix = "json: unknown field "
+		if strings.HasPrefix(msg, prefix) {
+			return &FieldError{Field: strings.Trim(strings.TrimPrefix(msg, prefix), `"`), Err: fmt.Errorf("unknown field")}
+		}
+	}
+	return err
+}
+
+// ParseJSON parses a JSON-encoded Config. Unknown keys are an error.
+func ParseJSON(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, decodeError(err)
+	}
+	return cfg, nil
+}
+
+// ParseYAML parses a YAML-encoded Config. The keys are the same as for JSON.
+func ParseYAML(data []byte) (Config, error) {
+	jsonData, err := yamlToJSON(data)
+	if err != nil {
+		return Config{}, err
+	}
+	return ParseJSON(jsonData)
+}
+
+// Load reads a Config from a file. Files with a .yaml or .yml extension are parsed as YAML, and all others as JSON.
+func Load(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+// LoadBandits reads a set of named bandit configurations from a file of the form:
+//	bandits:
+//	  homepage:
+//	    reward_source: ...
+//	    strategy: ...
+//	  checkout:
+//	    ...
+// Files with a .yaml or .yml extension are parsed as YAML, and all others as JSON.
+func LoadBandits(path string) (map[string]Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var v struct {
+		Bandits map[string]Config `json:"bandits"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return nil, decodeError(err)
+	}
+	return v.Bandits, nil
+}
+
+// Version returns a short hash of the configuration, which changes whenever any component type or parameter changes.
+func Version(cfg Config) string {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// yamlToJSON converts YAML to JSON, so that a single set of json tags describes both formats.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	converted, err := convertYAML(v, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+func convertYAML(v interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			k, ok := key.(string)
+			if !ok {
+				return nil, &FieldError{Field: path, Err: fmt.Errorf("keys must be strings. got=%v", key)}
+			}
+			converted, err := convertYAML(val, joinField(path, k))
+			if err != nil {
+				return nil, err
+			}
+			m[k] = converted
+		}
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			converted, err := convertYAML(val, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = converted
+		}
+		return s, nil
+	default:
+		return v, nil
+	}
+}
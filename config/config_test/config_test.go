@@ -0,0 +1,324 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/config"
+)
+
+const yamlConfig = `
+reward_source:
+  type: stub
+  rewards:
+    - {type: beta, alpha: 10, beta: 20}
+    - {type: beta, alpha: 20, beta: 10}
+strategy:
+  type: thompson
+  quadrature:
+    rule: gauss_legendre
+    degree: 6
+    subintervals: 4
+    abs_tol: 0.00001
+`
+
+func TestParseYAML_Build(t *testing.T) {
+	cfg, err := config.ParseYAML([]byte(yamlConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bandit, err := config.Build(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := bandit.Strategy.(*mab.Thompson); !ok {
+		t.Errorf("expected Thompson strategy. got=%T", bandit.Strategy)
+	}
+	if _, ok := bandit.Sampler.(*mab.Sha1Sampler); !ok {
+		t.Errorf("expected default Sha1Sampler. got=%T", bandit.Sampler)
+	}
+
+	res, err := bandit.SelectArm(context.Background(), "12345", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Probs) != 2 || res.Probs[1] < 0.9 {
+		t.Errorf("unexpected probabilities: %v", res.Probs)
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestParseJSON_Build_HTTP(t *testing.T) {
+	data := `{
+		"reward_source": {"type": "http", "url": "http://rewards/beta", "parser": "beta", "timeout": "50ms"},
+		"strategy": {"type": "epsilon_greedy", "epsilon": 0.1},
+		"sampler": {"type": "sha1"}
+	}`
+
+	cfg, err := config.ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requested string
+	client := doerFunc(func(r *http.Request) (*http.Response, error) {
+		requested = r.URL.String()
+		body := `[{"alpha": 10, "beta": 20}, {"alpha": 20, "beta": 10}]`
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+
+	bandit, err := config.NewBuilder(config.WithHTTPClient(client)).Build(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := bandit.SelectArm(context.Background(), "12345", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested != "http://rewards/beta" {
+		t.Errorf("unexpected request url %s", requested)
+	}
+	if len(res.Probs) != 2 || math.Abs(res.Probs[1]-0.95) > 1e-9 {
+		t.Errorf("unexpected probabilities: %v", res.Probs)
+	}
+}
+
+func TestBuild_Strategies(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected mab.Strategy
+	}{
+		"proportional": {`{"type": "proportional"}`, mab.NewProportional()},
+		"ucb":          {`{"type": "ucb", "alpha": 2}`, mab.NewUCB(2)},
+		"thompson_mc":  {`{"type": "thompson_mc", "iterations": 100}`, mab.NewThompsonMC(100)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := config.ParseJSON([]byte(`{"reward_source": {"type": "stub", "rewards": [{"type": "point", "mu": 1}]}, "strategy": ` + test.data + `}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			bandit, err := config.Build(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalStrategy(bandit.Strategy, test.expected) {
+				t.Errorf("expected %+v. got=%+v", test.expected, bandit.Strategy)
+			}
+		})
+	}
+}
+
+func equalStrategy(a, b mab.Strategy) bool {
+	switch a := a.(type) {
+	case *mab.UCB:
+		return a.Alpha == b.(*mab.UCB).Alpha
+	case *mab.ThompsonMC:
+		return a.NumIterations == b.(*mab.ThompsonMC).NumIterations
+	case *mab.Proportional:
+		_, ok := b.(*mab.Proportional)
+		return ok
+	}
+	return false
+}
+
+func TestBuild_FieldErrors(t *testing.T) {
+	stub := `{"type": "stub", "rewards": [{"type": "point", "mu": 1}]}`
+
+	tests := map[string]struct {
+		data  string
+		field string
+	}{
+		"missing source type":  {`{"reward_source": {}, "strategy": {"type": "proportional"}}`, "reward_source.type"},
+		"unknown strategy":     {`{"reward_source": ` + stub + `, "strategy": {"type": "magic"}}`, "strategy.type"},
+		"unknown sampler":      {`{"reward_source": ` + stub + `, "strategy": {"type": "proportional"}, "sampler": {"type": "md5"}}`, "sampler.type"},
+		"epsilon out of range": {`{"reward_source": ` + stub + `, "strategy": {"type": "epsilon_greedy", "epsilon": 2}}`, "strategy.epsilon"},
+		"missing epsilon":      {`{"reward_source": ` + stub + `, "strategy": {"type": "epsilon_greedy"}}`, "strategy.epsilon"},
+		"misspelled param":     {`{"reward_source": ` + stub + `, "strategy": {"type": "epsilon_greedy", "epsilom": 0.1}}`, "strategy.epsilom"},
+		"wrong param type":     {`{"reward_source": ` + stub + `, "strategy": {"type": "ucb", "alpha": "high"}}`, "strategy.alpha"},
+		"bad degree":           {`{"reward_source": ` + stub + `, "strategy": {"type": "thompson", "quadrature": {"rule": "newton_cotes_closed", "degree": 9}}}`, "strategy.quadrature.degree"},
+		"bad rule":             {`{"reward_source": ` + stub + `, "strategy": {"type": "thompson", "quadrature": {"rule": "simpson"}}}`, "strategy.quadrature.rule"},
+		"bad tolerance":        {`{"reward_source": ` + stub + `, "strategy": {"type": "thompson", "quadrature": {"rel_tol": -1}}}`, "strategy.quadrature.rel_tol"},
+		"missing url":          {`{"reward_source": {"type": "http", "parser": "beta"}, "strategy": {"type": "proportional"}}`, "reward_source.url"},
+		"unknown parser":       {`{"reward_source": {"type": "http", "url": "x", "parser": "gamma"}, "strategy": {"type": "proportional"}}`, "reward_source.parser"},
+		"bad timeout":          {`{"reward_source": {"type": "http", "url": "x", "parser": "beta", "timeout": "soon"}, "strategy": {"type": "proportional"}}`, "reward_source.timeout"},
+		"bad rewards":          {`{"reward_source": {"type": "stub", "rewards": [{"type": "beta", "alpha": -1, "beta": 1}]}, "strategy": {"type": "proportional"}}`, "reward_source.rewards"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := config.ParseJSON([]byte(test.data))
+			if err == nil {
+				_, err = config.Build(cfg)
+			}
+			var fe *config.FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected a FieldError. got=%v", err)
+			}
+			if fe.Field != test.field {
+				t.Errorf("expected field %s. got=%s (%v)", test.field, fe.Field, err)
+			}
+		})
+	}
+}
+
+func TestBuildQuadrature(t *testing.T) {
+	c, err := config.NewComponent("", map[string]interface{}{"rule": "newton_cotes_closed", "degree": 3, "subintervals": 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := config.BuildQuadrature(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := q.Integrate(func(x float64) float64 { return x * x }, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-9) > 1e-6 {
+		t.Errorf("expected 9. got=%f", result)
+	}
+
+	c, err = config.NewComponent("", map[string]interface{}{"degree": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fe *config.FieldError
+	if _, err := config.BuildQuadrature(c); !errors.As(err, &fe) || fe.Field != "degree" {
+		t.Errorf("expected a FieldError for degree. got=%v", err)
+	}
+}
+
+func TestParseJSON_UnknownField(t *testing.T) {
+	_, err := config.ParseJSON([]byte(`{"reward_sauce": {"type": "stub"}}`))
+	var fe *config.FieldError
+	if !errors.As(err, &fe) || fe.Field != "reward_sauce" {
+		t.Errorf("expected FieldError for reward_sauce. got=%v", err)
+	}
+}
+
+type constantSource struct {
+	value float64
+	arms  int
+}
+
+func (c constantSource) GetRewards(context.Context, interface{}) ([]mab.Dist, error) {
+	rewards := make([]mab.Dist, c.arms)
+	for i := range rewards {
+		rewards[i] = mab.Point(c.value)
+	}
+	return rewards, nil
+}
+
+func TestRegisterRewardSource(t *testing.T) {
+	config.RegisterRewardSource("constant", func(c config.Component, b *config.Builder) (mab.RewardSource, error) {
+		var p struct {
+			Value float64 `json:"value"`
+			Arms  int     `json:"arms"`
+		}
+		if err := c.Decode(&p); err != nil {
+			return nil, err
+		}
+		if p.Arms <= 0 {
+			return nil, config.FieldErrorf("arms", "must be > 0. got=%d", p.Arms)
+		}
+		return constantSource{p.Value, p.Arms}, nil
+	})
+
+	cfg, err := config.ParseJSON([]byte(`{"reward_source": {"type": "constant", "value": 0.5, "arms": 3}, "strategy": {"type": "proportional"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bandit, err := config.Build(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bandit.RewardSource != (constantSource{0.5, 3}) {
+		t.Errorf("unexpected reward source %+v", bandit.RewardSource)
+	}
+
+	cfg.RewardSource, _ = config.NewComponent("constant", map[string]interface{}{"arms": 0})
+	_, err = config.Build(cfg)
+	var fe *config.FieldError
+	if !errors.As(err, &fe) || fe.Field != "reward_source.arms" {
+		t.Errorf("expected FieldError for reward_source.arms. got=%v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate registration to panic")
+		}
+	}()
+	config.RegisterRewardSource("constant", func(config.Component, *config.Builder) (mab.RewardSource, error) { return nil, nil })
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	yamlPath := filepath.Join(dir, "bandit.yaml")
+	if err := ioutil.WriteFile(yamlPath, []byte(yamlConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := config.Load(yamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// round trip through JSON
+	data, err := json.Marshal(fromYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "bandit.json")
+	if err := ioutil.WriteFile(jsonPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := config.Load(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fromJSON.Strategy.Type != "thompson" || fromJSON.RewardSource.Type != "stub" {
+		t.Errorf("unexpected config %+v", fromJSON)
+	}
+	expectedParams := []string{"quadrature"}
+	if params := fromJSON.Strategy.Params(); len(params) != 1 || params[0] != expectedParams[0] {
+		t.Errorf("expected params %v. got=%v", expectedParams, params)
+	}
+	if _, err := config.Build(fromJSON); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	a, _ := config.ParseJSON([]byte(`{"reward_source": {"type": "stub", "rewards": []}, "strategy": {"type": "ucb", "alpha": 1}}`))
+	b, _ := config.ParseJSON([]byte(`{"strategy": {"alpha": 1, "type": "ucb"}, "reward_source": {"rewards": [], "type": "stub"}}`))
+	c, _ := config.ParseJSON([]byte(`{"reward_source": {"type": "stub", "rewards": []}, "strategy": {"type": "ucb", "alpha": 2}}`))
+
+	if config.Version(a) != config.Version(b) {
+		t.Error("equivalent configurations should have the same version")
+	}
+	if config.Version(a) == config.Version(c) {
+		t.Error("different configurations should have different versions")
+	}
+}
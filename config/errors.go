@@ -0,0 +1,49 @@
+package config
+
+import "fmt"
+
+// A FieldError is a configuration error for a specific field.
+// Field is the dotted path to the field from the root of the configuration, such as "strategy.quadrature.degree".
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrorf returns a FieldError for the field with a formatted message.
+// Factories for custom components should use it to report invalid parameters.
+func FieldErrorf(field string, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// inField prefixes the field of a FieldError with parent, or wraps any other error in a FieldError for parent.
+func inField(parent string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if fe, ok := err.(*FieldError); ok {
+		return &FieldError{Field: joinField(parent, fe.Field), Err: fe.Err}
+	}
+	return &FieldError{Field: parent, Err: err}
+}
+
+func joinField(parent, field string) string {
+	switch {
+	case parent == "":
+		return field
+	case field == "":
+		return parent
+	default:
+		return parent + "." + field
+	}
+}
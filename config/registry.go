@@ -0,0 +1,142 @@
+package config
+
+import (
+	"sync"
+
+	"github.com/stitchfix/mab"
+)
+
+// A RewardSourceFactory builds a RewardSource from its configuration.
+// Factories should decode their parameters with Component.Decode and report invalid parameters with FieldErrorf.
+type RewardSourceFactory func(c Component, b *Builder) (mab.RewardSource, error)
+
+// A StrategyFactory builds a Strategy from its configuration.
+type StrategyFactory func(c Component, b *Builder) (mab.Strategy, error)
+
+// A SamplerFactory builds a Sampler from its configuration.
+type SamplerFactory func(c Component, b *Builder) (mab.Sampler, error)
+
+// Built-in component types.
+const (
+	HTTPType          = "http"
+	StubType          = "stub"
+	ThompsonType      = "thompson"
+	ThompsonMCType    = "thompson_mc"
+	EpsilonGreedyType = "epsilon_greedy"
+	ProportionalType  = "proportional"
+	UCBType           = "ucb"
+	Sha1Type          = "sha1"
+)
+
+var (
+	registryMu sync.RWMutex
+
+	rewardSources = map[string]RewardSourceFactory{
+		HTTPType: buildHTTPSource,
+		StubType: buildStub,
+	}
+
+	strategies = map[string]StrategyFactory{
+		ThompsonType:      buildThompson,
+		ThompsonMCType:    buildThompsonMC,
+		EpsilonGreedyType: buildEpsilonGreedy,
+		ProportionalType:  buildProportional,
+		UCBType:           buildUCB,
+	}
+
+	samplers = map[string]SamplerFactory{
+		Sha1Type: buildSha1Sampler,
+	}
+
+	parsers = map[string]mab.RewardParser{
+		"beta":   mab.ParseFunc(mab.BetaFromJSON),
+		"normal": mab.ParseFunc(mab.NormalFromJSON),
+		"point":  mab.ParseFunc(mab.PointFromJSON),
+		"dists":  mab.ParseFunc(mab.DistsFromJSON),
+	}
+)
+
+// RegisterRewardSource makes a custom RewardSource available by type name.
+// If RegisterRewardSource is called twice with the same type name or if factory is nil, it panics.
+func RegisterRewardSource(typeName string, factory RewardSourceFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("config: RegisterRewardSource factory is nil")
+	}
+	if _, dup := rewardSources[typeName]; dup {
+		panic("config: RegisterRewardSource called twice for type " + typeName)
+	}
+	rewardSources[typeName] = factory
+}
+
+// RegisterStrategy makes a custom Strategy available by type name.
+// If RegisterStrategy is called twice with the same type name or if factory is nil, it panics.
+func RegisterStrategy(typeName string, factory StrategyFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("config: RegisterStrategy factory is nil")
+	}
+	if _, dup := strategies[typeName]; dup {
+		panic("config: RegisterStrategy called twice for type " + typeName)
+	}
+	strategies[typeName] = factory
+}
+
+// RegisterSampler makes a custom Sampler available by type name.
+// If RegisterSampler is called twice with the same type name or if factory is nil, it panics.
+func RegisterSampler(typeName string, factory SamplerFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if factory == nil {
+		panic("config: RegisterSampler factory is nil")
+	}
+	if _, dup := samplers[typeName]; dup {
+		panic("config: RegisterSampler called twice for type " + typeName)
+	}
+	samplers[typeName] = factory
+}
+
+// RegisterParser makes a custom RewardParser available by name for the parser of an HTTP reward source.
+// The built-in parsers are "beta", "normal", "point" and "dists".
+// If RegisterParser is called twice with the same name or if parser is nil, it panics.
+func RegisterParser(name string, parser mab.RewardParser) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if parser == nil {
+		panic("config: RegisterParser parser is nil")
+	}
+	if _, dup := parsers[name]; dup {
+		panic("config: RegisterParser called twice for name " + name)
+	}
+	parsers[name] = parser
+}
+
+func lookupRewardSource(typeName string) (RewardSourceFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := rewardSources[typeName]
+	return f, ok
+}
+
+func lookupStrategy(typeName string) (StrategyFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := strategies[typeName]
+	return f, ok
+}
+
+func lookupSampler(typeName string) (SamplerFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := samplers[typeName]
+	return f, ok
+}
+
+func lookupParser(name string) (mab.RewardParser, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	p, ok := parsers[name]
+	return p, ok
+}
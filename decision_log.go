@@ -0,0 +1,65 @@
+package mab
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// A DecisionLogger records the decisions made by a Bandit.
+// LogDecision is called after every call to Bandit.SelectArm, including calls that return an error, in which case the
+// Decision contains the partial Result.
+// LogDecision is called on the request path, so implementations should not block. They are responsible for handling
+// their own errors.
+type DecisionLogger interface {
+	LogDecision(ctx context.Context, d Decision)
+}
+
+// DecisionLoggerFunc is an adapter to allow a normal function to be used as a DecisionLogger.
+type DecisionLoggerFunc func(ctx context.Context, d Decision)
+
+func (f DecisionLoggerFunc) LogDecision(ctx context.Context, d Decision) { f(ctx, d) }
+
+// A Decision is a logged call to Bandit.SelectArm.
+// It contains everything needed for off-policy evaluation: the bandit context, the reward estimates,
+// the full vector of selection probabilities, and the selected arm.
+// Rewards observed later can be joined to the Decision by Result.DecisionID.
+// Bandit and Version are the Name and Version of the Bandit that made the decision, if they were set.
+type Decision struct {
+	Time          time.Time   `json:"time"`
+	Bandit        string      `json:"bandit,omitempty"`
+	Version       string      `json:"version,omitempty"`
+	Unit          string      `json:"unit"`
+	BanditContext interface{} `json:"context"`
+	Result        Result      `json:"result"`
+	Error         string      `json:"error,omitempty"`
+}
+
+// NewDecision returns a Decision made at the current time.
+func NewDecision(unit string, banditContext interface{}, result Result, err error) Decision {
+	d := Decision{
+		Time:          SystemClock.Now(),
+		Unit:          unit,
+		BanditContext: banditContext,
+		Result:        result,
+	}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	return d
+}
+
+// Propensity returns the probability with which the selected arm was chosen, or zero if no arm was selected.
+func (d Decision) Propensity() float64 {
+	return d.Result.Propensity()
+}
+
+// NewDecisionID returns a random 128-bit identifier encoded as a hexadecimal string.
+func NewDecisionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
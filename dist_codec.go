@@ -0,0 +1,390 @@
+package mab
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// The tagged JSON encoding of a Dist is a JSON object with a "type" key that names the distribution family,
+// plus the parameters of the distribution. The built-in distributions are encoded as:
+// 	{"type": "beta", "alpha": 40, "beta": 474}
+// 	{"type": "normal", "mu": 0.5, "sigma": 0.1}
+// 	{"type": "logit_normal", "mu": -2.1, "sigma": 0.3}
+// 	{"type": "point", "mu": 0.5}
+// 	{"type": "null"}
+// A Dist with a sample size, as returned by WithSampleSize, is encoded with the tagged encoding of the underlying Dist:
+// 	{"type": "sampled", "dist": {"type": "beta", "alpha": 40, "beta": 474}, "n": 512}
+// Custom Dist types can take part in the tagged encoding by implementing json.Marshaler so that the output includes
+// a "type" key, and by registering a DistDecoder for that type name with RegisterDist.
+const (
+	BetaType        = "beta"
+	NormalType      = "normal"
+	LogitNormalType = "logit_normal"
+	PointType       = "point"
+	NullType        = "null"
+	SampledType     = "sampled"
+)
+
+// A DistDecoder converts the tagged JSON encoding of a single distribution into a Dist.
+type DistDecoder interface {
+	DecodeDist(data []byte) (Dist, error)
+}
+
+// DistDecoderFunc is an adapter to allow a normal function to be used as a DistDecoder.
+type DistDecoderFunc func([]byte) (Dist, error)
+
+func (f DistDecoderFunc) DecodeDist(data []byte) (Dist, error) { return f(data) }
+
+var (
+	distDecodersMu sync.RWMutex
+	distDecoders   = map[string]DistDecoder{
+		BetaType:        DistDecoderFunc(decodeBeta),
+		NormalType:      DistDecoderFunc(decodeNormal),
+		LogitNormalType: DistDecoderFunc(decodeLogitNormal),
+		PointType:       DistDecoderFunc(decodePoint),
+		NullType:        DistDecoderFunc(decodePoint),
+		SampledType:     DistDecoderFunc(decodeSampled),
+	}
+)
+
+// RegisterDist makes a DistDecoder available for the given type name, so that DistFromJSON and DistsFromJSON can
+// decode custom distributions.
+// If RegisterDist is called twice with the same type name or if decoder is nil, it panics.
+func RegisterDist(typeName string, decoder DistDecoder) {
+	distDecodersMu.Lock()
+	defer distDecodersMu.Unlock()
+	if decoder == nil {
+		panic("mab: RegisterDist decoder is nil")
+	}
+	if _, dup := distDecoders[typeName]; dup {
+		panic("mab: RegisterDist called twice for type " + typeName)
+	}
+	distDecoders[typeName] = decoder
+}
+
+// RegisteredDists returns a sorted list of the type names that can be decoded.
+func RegisteredDists() []string {
+	distDecodersMu.RLock()
+	defer distDecodersMu.RUnlock()
+	names := make([]string, 0, len(distDecoders))
+	for name := range distDecoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MarshalDist returns the tagged JSON encoding of d.
+// Returns an error if d does not implement json.Marshaler.
+func MarshalDist(d Dist) ([]byte, error) {
+	m, ok := d.(json.Marshaler)
+	if !ok {
+		return nil, fmt.Errorf("cannot marshal %T: does not implement json.Marshaler", d)
+	}
+	return m.MarshalJSON()
+}
+
+// DistFromJSON converts the tagged JSON encoding of a single distribution to a Dist.
+// Returns an error if the "type" key is missing or if no DistDecoder is registered for the type.
+// Errors match ErrInvalidDist for invalid parameters, such as a negative sigma, and otherwise ErrRewardParse.
+func DistFromJSON(data []byte) (Dist, error) {
+	var tag struct {
+		Type *string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return nil, Errorf(ErrRewardParse, "failed to unmarshal distribution: %w", err)
+	}
+	if tag.Type == nil {
+		return nil, Errorf(ErrRewardParse, "missing distribution type")
+	}
+
+	distDecodersMu.RLock()
+	decoder, ok := distDecoders[*tag.Type]
+	distDecodersMu.RUnlock()
+
+	if !ok {
+		return nil, Errorf(ErrRewardParse, "unknown distribution type %q", *tag.Type)
+	}
+
+	dist, err := decoder.DecodeDist(data)
+	if err != nil && ErrorKind(err) == nil {
+		return nil, Errorf(ErrRewardParse, "%w", err)
+	}
+	return dist, err
+}
+
+// DistsFromJSON converts a JSON-encoded array of tagged distributions to a []Dist.
+// Unlike BetaFromJSON, NormalFromJSON and PointFromJSON, the arms may use different distribution families.
+// Expects the JSON data to be in the form:
+// 	`[{"type": "beta", "alpha": 123, "beta": 456}, {"type": "normal", "mu": 3.1415, "sigma": 9.999}, {"type": "null"}]`
+// Returns an error if any arm cannot be decoded.
+func DistsFromJSON(data []byte) ([]Dist, error) {
+	var resp []json.RawMessage
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, Errorf(ErrRewardParse, "failed to unmarshal response: %w", err)
+	}
+
+	result := make([]Dist, len(resp))
+
+	for i := range resp {
+		dist, err := DistFromJSON(resp[i])
+		if err != nil {
+			return nil, fmt.Errorf("arm %d: %w", i, err)
+		}
+		result[i] = dist
+	}
+
+	return result, nil
+}
+
+// MarshalDists returns the JSON encoding of a []Dist as an array of tagged distributions.
+func MarshalDists(dists []Dist) ([]byte, error) {
+	raw := make([]json.RawMessage, len(dists))
+	for i := range dists {
+		data, err := MarshalDist(dists[i])
+		if err != nil {
+			return nil, fmt.Errorf("arm %d: %w", i, err)
+		}
+		raw[i] = data
+	}
+	return json.Marshal(raw)
+}
+
+func checkType(typeName string, allowed ...string) error {
+	if typeName == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if typeName == a {
+			return nil
+		}
+	}
+	return Errorf(ErrRewardParse, "wrong distribution type %q. expected %q", typeName, allowed[0])
+}
+
+type betaJSON struct {
+	Type  string   `json:"type"`
+	Alpha *float64 `json:"alpha"`
+	Beta  *float64 `json:"beta"`
+}
+
+// MarshalJSON returns the tagged JSON encoding of the distribution.
+func (b BetaDist) MarshalJSON() ([]byte, error) {
+	return json.Marshal(betaJSON{BetaType, &b.Beta.Alpha, &b.Beta.Beta})
+}
+
+// UnmarshalJSON decodes the tagged JSON encoding of a beta distribution.
+// The "type" key may be omitted, but returns an error if it names a different distribution.
+// Returns an error if alpha or beta are missing or not positive.
+func (b *BetaDist) UnmarshalJSON(data []byte) error {
+	var v betaJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkType(v.Type, BetaType); err != nil {
+		return err
+	}
+	if v.Alpha == nil {
+		return Errorf(ErrRewardParse, "missing alpha value")
+	}
+	if v.Beta == nil {
+		return Errorf(ErrRewardParse, "missing beta value")
+	}
+	if *v.Alpha <= 0 {
+		return Errorf(ErrInvalidDist, "alpha must be > 0. got=%f", *v.Alpha)
+	}
+	if *v.Beta <= 0 {
+		return Errorf(ErrInvalidDist, "beta must be > 0. got=%f", *v.Beta)
+	}
+	*b = Beta(*v.Alpha, *v.Beta)
+	return nil
+}
+
+type normalJSON struct {
+	Type  string   `json:"type"`
+	Mu    *float64 `json:"mu"`
+	Sigma *float64 `json:"sigma"`
+}
+
+// MarshalJSON returns the tagged JSON encoding of the distribution.
+func (n NormalDist) MarshalJSON() ([]byte, error) {
+	return json.Marshal(normalJSON{NormalType, &n.Mu, &n.Sigma})
+}
+
+// UnmarshalJSON decodes the tagged JSON encoding of a normal distribution.
+// The "type" key may be omitted, but returns an error if it names a different distribution.
+// Returns an error if mu or sigma are missing or sigma is negative.
+func (n *NormalDist) UnmarshalJSON(data []byte) error {
+	var v normalJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkType(v.Type, NormalType); err != nil {
+		return err
+	}
+	if v.Mu == nil {
+		return Errorf(ErrRewardParse, "missing mu value")
+	}
+	if v.Sigma == nil {
+		return Errorf(ErrRewardParse, "missing sigma value")
+	}
+	if *v.Sigma < 0 {
+		return Errorf(ErrInvalidDist, "sigma must be >= 0. got=%f", *v.Sigma)
+	}
+	*n = Normal(*v.Mu, *v.Sigma)
+	return nil
+}
+
+// MarshalJSON returns the tagged JSON encoding of the distribution.
+func (l LogitNormalDist) MarshalJSON() ([]byte, error) {
+	return json.Marshal(normalJSON{LogitNormalType, &l.Mu, &l.Sigma})
+}
+
+// UnmarshalJSON decodes the tagged JSON encoding of a logit-normal distribution.
+// The "type" key may be omitted, but returns an error if it names a different distribution.
+// Returns an error if mu or sigma are missing, or if they are not valid according to Validate.
+func (l *LogitNormalDist) UnmarshalJSON(data []byte) error {
+	var v normalJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkType(v.Type, LogitNormalType); err != nil {
+		return err
+	}
+	if v.Mu == nil {
+		return Errorf(ErrRewardParse, "missing mu value")
+	}
+	if v.Sigma == nil {
+		return Errorf(ErrRewardParse, "missing sigma value")
+	}
+	if err := validateNormal(*v.Mu, *v.Sigma); err != nil {
+		return err
+	}
+	*l = LogitNormal(*v.Mu, *v.Sigma)
+	return nil
+}
+
+type pointJSON struct {
+	Type string   `json:"type"`
+	Mu   *float64 `json:"mu,omitempty"`
+}
+
+// MarshalJSON returns the tagged JSON encoding of the distribution.
+// Null distributions are encoded as {"type": "null"}, since JSON cannot represent negative infinity.
+func (p PointDist) MarshalJSON() ([]byte, error) {
+	if math.IsInf(p.Mu, -1) {
+		return json.Marshal(pointJSON{Type: NullType})
+	}
+	return json.Marshal(pointJSON{PointType, &p.Mu})
+}
+
+// UnmarshalJSON decodes the tagged JSON encoding of a point or null distribution.
+// The "type" key may be omitted for point distributions, but returns an error if it names a different distribution.
+// Returns an error if mu is missing for a point distribution.
+func (p *PointDist) UnmarshalJSON(data []byte) error {
+	var v pointJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkType(v.Type, PointType, NullType); err != nil {
+		return err
+	}
+	if v.Type == NullType {
+		*p = Null()
+		return nil
+	}
+	if v.Mu == nil {
+		return Errorf(ErrRewardParse, "missing mu value")
+	}
+	*p = Point(*v.Mu)
+	return nil
+}
+
+type sampledJSON struct {
+	Type string          `json:"type"`
+	Dist json.RawMessage `json:"dist"`
+	N    *float64        `json:"n"`
+}
+
+// MarshalJSON returns the tagged JSON encoding of the distribution, which includes the tagged encoding of the
+// underlying Dist.
+// Returns an error if the underlying Dist does not implement json.Marshaler.
+func (s SampledDist) MarshalJSON() ([]byte, error) {
+	dist, err := MarshalDist(s.Dist)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(sampledJSON{SampledType, dist, &s.N})
+}
+
+// UnmarshalJSON decodes the tagged JSON encoding of a distribution with a sample size.
+// The "type" key may be omitted, but returns an error if it names a different distribution.
+// Returns an error if the underlying distribution or n are missing, if the underlying distribution cannot be decoded
+// with DistFromJSON, or if n is negative.
+func (s *SampledDist) UnmarshalJSON(data []byte) error {
+	var v sampledJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkType(v.Type, SampledType); err != nil {
+		return err
+	}
+	if v.Dist == nil {
+		return Errorf(ErrRewardParse, "missing dist value")
+	}
+	if v.N == nil {
+		return Errorf(ErrRewardParse, "missing n value")
+	}
+	if *v.N < 0 {
+		return Errorf(ErrInvalidDist, "n must be >= 0. got=%f", *v.N)
+	}
+	dist, err := DistFromJSON(v.Dist)
+	if err != nil {
+		return err
+	}
+	*s = WithSampleSize(dist, *v.N)
+	return nil
+}
+
+func decodeBeta(data []byte) (Dist, error) {
+	var d BetaDist
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func decodeNormal(data []byte) (Dist, error) {
+	var d NormalDist
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func decodeLogitNormal(data []byte) (Dist, error) {
+	var d LogitNormalDist
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func decodePoint(data []byte) (Dist, error) {
+	var d PointDist
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func decodeSampled(data []byte) (Dist, error) {
+	var d SampledDist
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -26,6 +26,11 @@ func (n NormalDist) String() string {
 	return fmt.Sprintf("Normal(%f,%f)", n.Mu, n.Sigma)
 }
 
+// Validate returns an error if mu is not finite or if sigma is not finite and positive.
+func (n NormalDist) Validate() error {
+	return validateNormal(n.Mu, n.Sigma)
+}
+
 // Beta is a beta distribution for use with any bandit strategy.
 func Beta(alpha, beta float64) BetaDist {
 	return BetaDist{distuv.Beta{Alpha: alpha, Beta: beta}}
@@ -43,6 +48,93 @@ func (b BetaDist) String() string {
 	return fmt.Sprintf("Beta(%f,%f)", b.Beta.Alpha, b.Beta.Beta)
 }
 
+// SampleSize returns alpha + beta, the number of observations the distribution is based on, including the
+// pseudo-observations of the prior.
+func (b BetaDist) SampleSize() float64 {
+	return b.Alpha + b.Beta.Beta
+}
+
+// Validate returns an error if alpha or beta are not finite and positive.
+func (b BetaDist) Validate() error {
+	if !isPositive(b.Alpha) {
+		return Errorf(ErrInvalidDist, "alpha must be finite and > 0. got=%f", b.Alpha)
+	}
+	if !isPositive(b.Beta.Beta) {
+		return Errorf(ErrInvalidDist, "beta must be finite and > 0. got=%f", b.Beta.Beta)
+	}
+	return nil
+}
+
+// LogitNormal is the distribution of sigmoid(z), where z is normally distributed with mean mu and standard deviation sigma.
+// It is the predictive distribution of a reward probability under a logistic model with a Gaussian posterior.
+// For the purposes of Thompson sampling, it is truncated at sigmoid(mu +/- 4*sigma)
+func LogitNormal(mu, sigma float64) LogitNormalDist {
+	return LogitNormalDist{Mu: mu, Sigma: sigma}
+}
+
+// LogitNormalDist is a logit-normal distribution on the interval (0, 1), with the mean Mu and standard deviation Sigma
+// of the underlying normal distribution on the logit scale.
+type LogitNormalDist struct {
+	Mu, Sigma float64
+}
+
+// CDF returns the cumulative distribution function evaluated at x.
+func (l LogitNormalDist) CDF(x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	return l.normal().CDF(logit(x))
+}
+
+// Mean returns an approximation of the mean of the distribution, sigmoid(mu / sqrt(1 + pi*sigma^2/8)).
+// The mean of the logit-normal distribution has no closed form.
+func (l LogitNormalDist) Mean() float64 {
+	return sigmoid(l.Mu / math.Sqrt(1+math.Pi*l.Sigma*l.Sigma/8))
+}
+
+// Prob returns the probability density function evaluated at x.
+func (l LogitNormalDist) Prob(x float64) float64 {
+	if x <= 0 || x >= 1 {
+		return 0
+	}
+	return l.normal().Prob(logit(x)) / (x * (1 - x))
+}
+
+// Rand returns a pseudo-random sample drawn from the distribution.
+func (l LogitNormalDist) Rand() float64 {
+	return sigmoid(l.normal().Rand())
+}
+
+// Support returns the interval from sigmoid(mu - 4*sigma) to sigmoid(mu + 4*sigma).
+func (l LogitNormalDist) Support() (float64, float64) {
+	width := 4.0
+	return sigmoid(l.Mu - width*l.Sigma), sigmoid(l.Mu + width*l.Sigma)
+}
+
+func (l LogitNormalDist) String() string {
+	return fmt.Sprintf("LogitNormal(%f,%f)", l.Mu, l.Sigma)
+}
+
+// Validate returns an error if mu is not finite or if sigma is not finite and positive.
+func (l LogitNormalDist) Validate() error {
+	return validateNormal(l.Mu, l.Sigma)
+}
+
+func (l LogitNormalDist) normal() distuv.Normal {
+	return distuv.Normal{Mu: l.Mu, Sigma: l.Sigma}
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func logit(p float64) float64 {
+	return math.Log(p / (1 - p))
+}
+
 // Point is used for reward models that just provide point estimates. Don't use with Thompson sampling.
 func Point(mu float64) PointDist {
 	return PointDist{mu}
@@ -85,8 +177,30 @@ func (p PointDist) String() string {
 	return fmt.Sprintf("Point(%f)", p.Mu)
 }
 
+// Validate returns an error if mu is NaN or positive infinity. A mu of negative infinity is valid, since it is Null.
+func (p PointDist) Validate() error {
+	if math.IsNaN(p.Mu) || math.IsInf(p.Mu, 1) {
+		return Errorf(ErrInvalidDist, "mu must be finite or -Inf. got=%f", p.Mu)
+	}
+	return nil
+}
+
 // Null returns a PointDist with mean equal to negative infinity. This is a special value that indicates
 // to a Strategy that this arm should get selection probability zero.
 func Null() PointDist {
 	return PointDist{math.Inf(-1)}
 }
+
+func validateNormal(mu, sigma float64) error {
+	if math.IsNaN(mu) || math.IsInf(mu, 0) {
+		return Errorf(ErrInvalidDist, "mu must be finite. got=%f", mu)
+	}
+	if !isPositive(sigma) {
+		return Errorf(ErrInvalidDist, "sigma must be finite and > 0. got=%f", sigma)
+	}
+	return nil
+}
+
+func isPositive(x float64) bool {
+	return x > 0 && !math.IsInf(x, 1)
+}
@@ -1,9 +1,6 @@
 package mab
 
-import (
-	"fmt"
-	"math"
-)
+import "math"
 
 func NewEpsilonGreedy(e float64) *EpsilonGreedy {
 	return &EpsilonGreedy{
@@ -81,7 +78,7 @@ func (e EpsilonGreedy) numNonNullArms() int {
 
 func (e EpsilonGreedy) validateEpsilon() error {
 	if e.Epsilon < 0 || e.Epsilon > 1 {
-		return fmt.Errorf("invalid Epsilon value: %v. Must be between 0 and 1", e.Epsilon)
+		return Errorf(ErrInvalidParameter, "invalid Epsilon value: %v. Must be between 0 and 1", e.Epsilon)
 	}
 	return nil
 }
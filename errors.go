@@ -0,0 +1,100 @@
+package mab
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors that classify the failures of Bandit.SelectArm.
+// The errors returned by the built-in components match one of them with errors.Is, and still wrap their underlying
+// cause, so that, for example, a timed out reward request matches both ErrRewardFetch and context.DeadlineExceeded.
+var (
+	// ErrRewardFetch is a failure to get reward estimates, such as a network error or a non-2XX response.
+	ErrRewardFetch = errors.New("reward fetch failed")
+
+	// ErrRewardParse is a reward response that cannot be decoded.
+	ErrRewardParse = errors.New("reward parse failed")
+
+	// ErrInvalidContext is a banditContext that the RewardSource cannot use.
+	ErrInvalidContext = errors.New("invalid bandit context")
+
+	// ErrInvalidDist is a reward distribution with invalid parameters, or that the Strategy cannot use.
+	ErrInvalidDist = errors.New("invalid distribution")
+
+	// ErrInvalidParameter is a component that was configured with an invalid parameter, such as an epsilon > 1.
+	ErrInvalidParameter = errors.New("invalid parameter")
+
+	// ErrNotConverged is a Strategy whose numerical integration failed to converge.
+	ErrNotConverged = errors.New("failed to converge")
+
+	// ErrInvalidProbs is a set of selection probabilities that cannot be sampled from, such as negative weights.
+	ErrInvalidProbs = errors.New("invalid probabilities")
+
+	// ErrSampler is any other failure of a Sampler.
+	ErrSampler = errors.New("sampler failed")
+)
+
+var errorKinds = []error{
+	ErrRewardFetch,
+	ErrRewardParse,
+	ErrInvalidContext,
+	ErrInvalidDist,
+	ErrInvalidParameter,
+	ErrNotConverged,
+	ErrInvalidProbs,
+	ErrSampler,
+}
+
+// ErrorKind returns the sentinel error that classifies err, or nil if err is not classified.
+func ErrorKind(err error) error {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// An Error is an error of the class given by Kind, which is one of the sentinel errors such as ErrRewardParse.
+// Err is the underlying cause, if any. errors.Is matches both the Kind and the cause.
+type Error struct {
+	Kind error
+	Msg  string
+	Err  error
+}
+
+// Errorf returns an *Error of the given kind with a message formatted by fmt.Errorf. If the format has a %w verb,
+// the wrapped error is the cause.
+// Custom components can use Errorf to classify their errors in the same way as the built-in components.
+func Errorf(kind error, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Kind: kind, Msg: err.Error(), Err: errors.Unwrap(err)}
+}
+
+func (e *Error) Error() string {
+	if e.Msg == "" {
+		return e.Kind.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func (e *Error) Is(target error) bool { return target == e.Kind }
+
+// A SelectError is returned by Bandit.SelectArm when one of its stages fails.
+// Bandit is the Name of the Bandit, and Err is the error returned by the component that ran the stage.
+type SelectError struct {
+	Bandit string
+	Stage  Stage
+	Err    error
+}
+
+func (e *SelectError) Error() string {
+	if e.Bandit == "" {
+		return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("bandit %q: %s: %v", e.Bandit, e.Stage, e.Err)
+}
+
+func (e *SelectError) Unwrap() error { return e.Err }
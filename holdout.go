@@ -0,0 +1,54 @@
+package mab
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"math"
+)
+
+// A Holdout routes a fixed fraction of units away from the bandit, so that the bandit's cumulative lift can be measured
+// against them.
+// Membership is determined by the SHA1 hash of Salt and the unit, which is independent of the hash used by
+// Sha1Sampler as long as Salt is non-empty. The same unit is always in or out of the holdout for a given Salt and
+// Fraction, and increasing Fraction only adds units to the holdout.
+// Held-out units get the ControlArm, unless Uniform is true, in which case they get an arm selected uniformly at random
+// from the arms that are not Null or filtered. The ControlArm is served even if it is Null or filtered, and must be
+// the index of one of the arms in the reward estimates.
+type Holdout struct {
+	Fraction   float64
+	Salt       string
+	ControlArm int
+	Uniform    bool
+}
+
+// Contains returns true if the unit is in the holdout.
+func (h *Holdout) Contains(unit string) bool {
+	checkSum := sha1.Sum([]byte(h.Salt + ":" + unit))
+	u := float64(binary.BigEndian.Uint64(checkSum[0:8])>>11) / (1 << 53)
+	return u < h.Fraction
+}
+
+// validateControlArm returns an error if the ControlArm is not the index of one of numArms arms.
+func (h *Holdout) validateControlArm(numArms int) error {
+	if h.ControlArm < 0 || h.ControlArm >= numArms {
+		return Errorf(ErrInvalidParameter, "holdout control arm %d out of range [0, %d)", h.ControlArm, numArms)
+	}
+	return nil
+}
+
+// uniformProbs returns equal probabilities for all arms that do not have a Null distribution.
+func uniformProbs(rewards []Dist) []float64 {
+	probs := make([]float64, len(rewards))
+	n := 0
+	for _, r := range rewards {
+		if !math.IsInf(r.Mean(), -1) {
+			n++
+		}
+	}
+	for i, r := range rewards {
+		if !math.IsInf(r.Mean(), -1) {
+			probs[i] = 1 / float64(n)
+		}
+	}
+	return probs
+}
@@ -42,6 +42,8 @@ type HTTPSource struct {
 
 // GetRewards makes a POST request to the reward URL, and parses the response into a []Dist.
 // If a banditContext is provided, it will be marshaled and included in the body of the request.
+// Errors making the request match ErrRewardFetch, and errors from the RewardParser that are not already classified
+// match ErrRewardParse.
 func (h *HTTPSource) GetRewards(ctx context.Context, banditContext interface{}) ([]Dist, error) {
 
 	var body io.Reader
@@ -49,25 +51,25 @@ func (h *HTTPSource) GetRewards(ctx context.Context, banditContext interface{})
 	if banditContext != nil {
 		marshaled, err := h.marshaler.Marshal(banditContext)
 		if err != nil {
-			return nil, err
+			return nil, Errorf(ErrInvalidContext, "failed to marshal bandit context: %w", err)
 		}
 		body = bytes.NewBuffer(marshaled)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", h.url, body)
 	if err != nil {
-		return nil, err
+		return nil, Errorf(ErrRewardFetch, "%w", err)
 	}
 
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, Errorf(ErrRewardFetch, "%w", err)
 	}
 	defer resp.Body.Close()
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, Errorf(ErrRewardFetch, "failed to read reward response: %w", err)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
@@ -78,9 +80,15 @@ func (h *HTTPSource) GetRewards(ctx context.Context, banditContext interface{})
 		}
 	}
 
-	return h.parser.Parse(data)
+	rewards, err := h.parser.Parse(data)
+	if err != nil && ErrorKind(err) == nil {
+		return rewards, Errorf(ErrRewardParse, "%w", err)
+	}
+	return rewards, err
 }
 
+// ErrRewardNon2XX is returned by HTTPSource when the reward service responds with a non-2XX status code.
+// It matches ErrRewardFetch with errors.Is.
 type ErrRewardNon2XX struct {
 	Url        string
 	StatusCode int
@@ -91,6 +99,8 @@ func (e *ErrRewardNon2XX) Error() string {
 	return fmt.Sprintf("reward service \"%s\": [%d] %s", e.Url, e.StatusCode, e.RespBody)
 }
 
+func (e *ErrRewardNon2XX) Is(target error) bool { return target == ErrRewardFetch }
+
 // HTTPDoer is a basic interface for making HTTP requests. The net/http Client can be used or you can bring your own.
 // Heimdall is a pretty good alternative client with some nice features: https://github.com/gojek/heimdall
 type HttpDoer interface {
@@ -138,23 +148,23 @@ func BetaFromJSON(data []byte) ([]Dist, error) {
 		Beta  *float64 `json:"beta"`
 	}
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, Errorf(ErrRewardParse, "failed to unmarshal response: %w", err)
 	}
 
 	result := make([]Dist, len(resp))
 
 	for i := range resp {
 		if resp[i].Alpha == nil {
-			return result, fmt.Errorf("missing alpha value for arm %d", i)
+			return result, Errorf(ErrRewardParse, "missing alpha value for arm %d", i)
 		}
 		if resp[i].Beta == nil {
-			return result, fmt.Errorf("missing beta value for arm %d", i)
+			return result, Errorf(ErrRewardParse, "missing beta value for arm %d", i)
 		}
 		if *resp[i].Alpha < 1 {
-			return result, fmt.Errorf("arm %d alpha must be > 1. got=%f", i, *resp[i].Alpha)
+			return result, Errorf(ErrInvalidDist, "arm %d alpha must be > 1. got=%f", i, *resp[i].Alpha)
 		}
 		if *resp[i].Beta < 1 {
-			return result, fmt.Errorf("arm %d beta must be > 1. got=%f", i, *resp[i].Beta)
+			return result, Errorf(ErrInvalidDist, "arm %d beta must be > 1. got=%f", i, *resp[i].Beta)
 		}
 		result[i] = Beta(*resp[i].Alpha, *resp[i].Beta)
 	}
@@ -173,20 +183,20 @@ func NormalFromJSON(data []byte) ([]Dist, error) {
 		Sigma *float64 `json:"sigma"`
 	}
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, Errorf(ErrRewardParse, "failed to unmarshal response: %w", err)
 	}
 
 	result := make([]Dist, 0)
 
 	for i := range resp {
 		if resp[i].Mu == nil {
-			return result, fmt.Errorf("missing mu value for arm %d", i)
+			return result, Errorf(ErrRewardParse, "missing mu value for arm %d", i)
 		}
 		if resp[i].Sigma == nil {
-			return result, fmt.Errorf("missing sigma value for arm %d", i)
+			return result, Errorf(ErrRewardParse, "missing sigma value for arm %d", i)
 		}
 		if *resp[i].Sigma < 0 {
-			return result, fmt.Errorf("arm %d sigma must be > 0. got=%f", i, *resp[i].Sigma)
+			return result, Errorf(ErrInvalidDist, "arm %d sigma must be > 0. got=%f", i, *resp[i].Sigma)
 		}
 		result = append(result, Normal(*resp[i].Mu, *resp[i].Sigma))
 	}
@@ -203,14 +213,14 @@ func PointFromJSON(data []byte) ([]Dist, error) {
 		Mu *float64
 	}
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, Errorf(ErrRewardParse, "failed to unmarshal response: %w", err)
 	}
 
 	result := make([]Dist, 0)
 
 	for i := range resp {
 		if resp[i].Mu == nil {
-			return result, fmt.Errorf("missing mu value for arm %d", i)
+			return result, Errorf(ErrRewardParse, "missing mu value for arm %d", i)
 		}
 		result = append(result, Point(*resp[i].Mu))
 	}
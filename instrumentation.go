@@ -0,0 +1,119 @@
+package mab
+
+import (
+	"context"
+	"time"
+)
+
+// A Stage is a step of Bandit.SelectArm.
+type Stage string
+
+// The stages of Bandit.SelectArm, in the order they run.
+// The override stage only runs if the Bandit has an Overrider, and the filter stage only runs if it has ArmFilters.
+// Units that get a forced arm or a control arm skip the remaining stages.
+const (
+	StageOverride Stage = "override"
+	StageRewards  Stage = "rewards"
+	StageFilter   Stage = "filter"
+	StageProbs    Stage = "probs"
+	StageSample   Stage = "sample"
+)
+
+// A StageEvent describes a completed stage of a call to Bandit.SelectArm.
+type StageEvent struct {
+	Bandit   string
+	Stage    Stage
+	Duration time.Duration
+	Err      error
+}
+
+// A SelectEvent describes a completed call to Bandit.SelectArm.
+// Result is the partial result if Err is not nil.
+type SelectEvent struct {
+	Bandit   string
+	Version  string
+	Unit     string
+	Result   Result
+	Duration time.Duration
+	Err      error
+}
+
+// Instrumentation receives the timings and outcomes of the stages of Bandit.SelectArm, for metrics and tracing.
+// StartSelect is called at the start of SelectArm, and StartStage at the start of each stage. Each returns the
+// context used until the matching End call, so that an implementation can store a span in the context. The
+// context returned by StartStage is also passed to the component that runs the stage, so spans started by an
+// external reward service client are children of the stage's span.
+// The methods are called on the request path, so implementations should not block.
+type Instrumentation interface {
+	StartSelect(ctx context.Context, bandit string, unit string) context.Context
+	EndSelect(ctx context.Context, e SelectEvent)
+	StartStage(ctx context.Context, stage Stage) context.Context
+	EndStage(ctx context.Context, e StageEvent)
+}
+
+// MultiInstrumentation returns Instrumentation that calls each of the given Instrumentations in order, such as
+// a tracer and a metrics collector. Contexts are threaded through them, so each can add its own values.
+func MultiInstrumentation(instrumentations ...Instrumentation) Instrumentation {
+	return multiInstrumentation(instrumentations)
+}
+
+type multiInstrumentation []Instrumentation
+
+func (m multiInstrumentation) StartSelect(ctx context.Context, bandit string, unit string) context.Context {
+	for _, i := range m {
+		ctx = i.StartSelect(ctx, bandit, unit)
+	}
+	return ctx
+}
+
+func (m multiInstrumentation) EndSelect(ctx context.Context, e SelectEvent) {
+	for _, i := range m {
+		i.EndSelect(ctx, e)
+	}
+}
+
+func (m multiInstrumentation) StartStage(ctx context.Context, stage Stage) context.Context {
+	for _, i := range m {
+		ctx = i.StartStage(ctx, stage)
+	}
+	return ctx
+}
+
+func (m multiInstrumentation) EndStage(ctx context.Context, e StageEvent) {
+	for _, i := range m {
+		i.EndStage(ctx, e)
+	}
+}
+
+// nopInstrumentation is used when a Bandit has no Instrumentation.
+type nopInstrumentation struct{}
+
+func (nopInstrumentation) StartSelect(ctx context.Context, bandit string, unit string) context.Context {
+	return ctx
+}
+
+func (nopInstrumentation) EndSelect(ctx context.Context, e SelectEvent) {}
+
+func (nopInstrumentation) StartStage(ctx context.Context, stage Stage) context.Context { return ctx }
+
+func (nopInstrumentation) EndStage(ctx context.Context, e StageEvent) {}
+
+func (b *Bandit) instrumentation() Instrumentation {
+	if b.Instrumentation == nil {
+		return nopInstrumentation{}
+	}
+	return b.Instrumentation
+}
+
+// stage runs f as the given stage of SelectArm, with the stage's context.
+// An error from f is returned as a *SelectError for the stage.
+func (b *Bandit) stage(ctx context.Context, inst Instrumentation, stage Stage, f func(ctx context.Context) error) error {
+	start := time.Now()
+	ctx = inst.StartStage(ctx, stage)
+	err := f(ctx)
+	inst.EndStage(ctx, StageEvent{Bandit: b.Name, Stage: stage, Duration: time.Since(start), Err: err})
+	if err != nil {
+		return &SelectError{Bandit: b.Name, Stage: stage, Err: err}
+	}
+	return nil
+}
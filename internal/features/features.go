@@ -0,0 +1,46 @@
+// Package features validates the feature vectors and observations of the contextual reward models.
+package features
+
+import (
+	"math"
+
+	"github.com/stitchfix/mab"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Vector returns the banditContext as a feature vector of length dim.
+// Returns an error matching mab.ErrInvalidContext if the banditContext is not a []float64 or mat.Vector, if it has the
+// wrong length, or if any feature is not finite.
+func Vector(banditContext interface{}, dim int) (mat.Vector, error) {
+	var x mat.Vector
+	switch v := banditContext.(type) {
+	case []float64:
+		x = mat.NewVecDense(len(v), v)
+	case mat.Vector:
+		x = v
+	default:
+		return nil, mab.Errorf(mab.ErrInvalidContext, "banditContext must be a []float64 or mat.Vector. got=%T", banditContext)
+	}
+
+	if x.Len() != dim {
+		return nil, mab.Errorf(mab.ErrInvalidContext, "feature vector must have length %d. got=%d", dim, x.Len())
+	}
+
+	for i := 0; i < x.Len(); i++ {
+		if math.IsNaN(x.AtVec(i)) || math.IsInf(x.AtVec(i), 0) {
+			return nil, mab.Errorf(mab.ErrInvalidContext, "feature %d must be finite. got=%f", i, x.AtVec(i))
+		}
+	}
+
+	return x, nil
+}
+
+// Observation validates the arm index of an observation for one of numArms arms, and returns the banditContext as a
+// feature vector of length dim.
+// Returns an error matching mab.ErrInvalidParameter if the arm index is out of range, or an error from Vector.
+func Observation(banditContext interface{}, dim, arm, numArms int) (mat.Vector, error) {
+	if arm < 0 || arm >= numArms {
+		return nil, mab.Errorf(mab.ErrInvalidParameter, "arm index %d out of range [0, %d)", arm, numArms)
+	}
+	return Vector(banditContext, dim)
+}
@@ -0,0 +1,314 @@
+package mab
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultLogBufferSize = 1024
+	rotatedFileTimeFmt   = "20060102T150405.000000000"
+)
+
+// NewJSONLLogger returns a new JSONLLogger that appends decisions to the file at path, creating it if necessary.
+// Options can be used to enable size- or time-based rotation and to customize buffering and error handling.
+// Returns an error if the buffer size is less than 1, or if the file cannot be opened.
+// For example, to rotate the log every 100MB:
+//	logger, err := NewJSONLLogger("/var/log/bandit/decisions.jsonl", WithMaxBytes(100<<20))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer logger.Close()
+//
+//	bandit.Logger = logger
+func NewJSONLLogger(path string, opts ...JSONLLoggerOption) (*JSONLLogger, error) {
+	l := &JSONLLogger{
+		path:       path,
+		bufferSize: defaultLogBufferSize,
+		clock:      SystemClock,
+		onError:    func(error) {},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if l.bufferSize < 1 {
+		return nil, Errorf(ErrInvalidParameter, "invalid buffer size: %d. Must be at least 1", l.bufferSize)
+	}
+
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	l.lines = make(chan []byte, l.bufferSize)
+	l.done = make(chan struct{})
+	go l.run()
+
+	return l, nil
+}
+
+// JSONLLogger is a DecisionLogger that writes each Decision as a line of JSON to a file.
+// Decisions are queued and written by a background goroutine, so LogDecision never blocks on disk I/O.
+// If the queue is full, the decision is dropped and reported to the error handler.
+// Probabilities that are NaN or infinite cannot be encoded as JSON, so they are logged as 0, which leaves the decision
+// with zero propensity.
+// If the log file cannot be reopened after a rotation, the decisions are dropped and the error is reported until it can
+// be reopened, which is tried again for each decision.
+// Close must be called to flush the queued decisions.
+type JSONLLogger struct {
+	path        string
+	bufferSize  int
+	maxBytes    int64
+	rotateEvery time.Duration
+	clock       Clock
+	onError     func(error)
+	dropped     uint64
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	lines     chan []byte
+	done      chan struct{}
+
+	// owned by the writer goroutine. file and writer are nil if the file could not be reopened after a rotation.
+	file     *os.File
+	writer   *bufio.Writer
+	size     int64
+	openedAt time.Time
+}
+
+// LogDecision queues the decision to be written to the log file.
+func (l *JSONLLogger) LogDecision(ctx context.Context, d Decision) {
+	line, err := json.Marshal(finiteProbs(d))
+	if err != nil {
+		l.onError(fmt.Errorf("failed to marshal decision %s: %w", d.Result.DecisionID, err))
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		l.onError(fmt.Errorf("decision %s not logged: logger is closed", d.Result.DecisionID))
+		return
+	}
+
+	select {
+	case l.lines <- line:
+	default:
+		atomic.AddUint64(&l.dropped, 1)
+		l.onError(fmt.Errorf("decision %s dropped: log buffer is full", d.Result.DecisionID))
+	}
+}
+
+// finiteProbs returns d with any probabilities that are not finite replaced by 0.
+func finiteProbs(d Decision) Decision {
+	var probs []float64
+	for i, p := range d.Result.Probs {
+		if !math.IsNaN(p) && !math.IsInf(p, 0) {
+			continue
+		}
+		if probs == nil {
+			probs = append([]float64(nil), d.Result.Probs...)
+		}
+		probs[i] = 0
+	}
+	if probs != nil {
+		d.Result.Probs = probs
+	}
+	return d
+}
+
+// Dropped returns the number of decisions that were dropped because the queue was full.
+func (l *JSONLLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Close stops accepting new decisions, writes all queued decisions, and closes the log file.
+func (l *JSONLLogger) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		l.mu.Lock()
+		l.closed = true
+		close(l.lines)
+		l.mu.Unlock()
+
+		<-l.done
+		err = l.closeFile()
+	})
+	return err
+}
+
+func (l *JSONLLogger) run() {
+	defer close(l.done)
+	for line := range l.lines {
+		if err := l.write(line); err != nil {
+			l.onError(err)
+		}
+		if len(l.lines) == 0 && l.writer != nil {
+			if err := l.writer.Flush(); err != nil {
+				l.onError(err)
+			}
+		}
+	}
+}
+
+func (l *JSONLLogger) write(line []byte) error {
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return err
+		}
+	}
+	if l.shouldRotate(len(line)) {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.writer.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+func (l *JSONLLogger) shouldRotate(n int) bool {
+	if l.size == 0 {
+		return false
+	}
+	if l.maxBytes > 0 && l.size+int64(n) > l.maxBytes {
+		return true
+	}
+	return l.rotateEvery > 0 && l.clock.Now().Sub(l.openedAt) >= l.rotateEvery
+}
+
+// rotate closes the current file, renames it with a timestamp suffix, and opens a new file at the original path.
+// If an error is returned, the file may not be open.
+func (l *JSONLLogger) rotate() error {
+	if err := l.closeFile(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.path, l.rotatedName()); err != nil {
+		if openErr := l.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate decision log: %w", err)
+	}
+	return l.open()
+}
+
+// rotatedName returns the path for a rotated file, adding a counter if a file with the same timestamp already exists.
+func (l *JSONLLogger) rotatedName() string {
+	name := l.path + "." + l.clock.Now().UTC().Format(rotatedFileTimeFmt)
+	candidate := name
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s.%d", name, i)
+	}
+}
+
+func (l *JSONLLogger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open decision log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open decision log: %w", err)
+	}
+	l.file = f
+	l.writer = bufio.NewWriter(f)
+	l.size = info.Size()
+	l.openedAt = l.clock.Now()
+	return nil
+}
+
+func (l *JSONLLogger) closeFile() error {
+	if l.file == nil {
+		return nil
+	}
+	err := l.writer.Flush()
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file, l.writer = nil, nil
+	return err
+}
+
+// JSONLLoggerOption allows for optional arguments to NewJSONLLogger
+type JSONLLoggerOption func(*JSONLLogger)
+
+// WithMaxBytes rotates the log file before a write would make it larger than maxBytes.
+func WithMaxBytes(maxBytes int64) JSONLLoggerOption {
+	return func(l *JSONLLogger) {
+		l.maxBytes = maxBytes
+	}
+}
+
+// WithRotateEvery rotates the log file once it has been open for longer than d.
+func WithRotateEvery(d time.Duration) JSONLLoggerOption {
+	return func(l *JSONLLogger) {
+		l.rotateEvery = d
+	}
+}
+
+// WithBufferSize sets the number of decisions that can be queued before new decisions are dropped. The default is 1024.
+// It must be at least 1.
+func WithBufferSize(n int) JSONLLoggerOption {
+	return func(l *JSONLLogger) {
+		l.bufferSize = n
+	}
+}
+
+// WithErrorHandler sets a function to be called with errors encountered while logging, including dropped decisions.
+// The handler may be called from the background writer goroutine. By default, errors are ignored.
+func WithErrorHandler(f func(error)) JSONLLoggerOption {
+	return func(l *JSONLLogger) {
+		l.onError = f
+	}
+}
+
+// WithLogClock sets the Clock used for time-based rotation and for naming rotated files.
+func WithLogClock(c Clock) JSONLLoggerOption {
+	return func(l *JSONLLogger) {
+		l.clock = c
+	}
+}
+
+// ReadDecisions reads a JSONL decision log, calling f for each decision in order.
+// Reading stops at the first error returned by f.
+func ReadDecisions(path string, f func(Decision) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var d Decision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := f(d); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,53 @@
+package mab
+
+import (
+	"context"
+	"reflect"
+)
+
+// A Learner is a bandit component that learns from observed rewards.
+// RewardSources, Strategies and Samplers may implement Learner to receive feedback through Bandit.Observe.
+// The arm is the index of the arm that was selected for the bandit context, the reward is the observed outcome,
+// and the propensity is the probability with which the arm was selected, as reported in Result.Probs.
+// Learners that do not need the propensity may ignore it.
+type Learner interface {
+	Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error
+}
+
+// Observe routes an observed reward to each of the bandit's components that implement Learner.
+// The RewardSource, Strategy and Sampler are updated in that order, and a component that fills more than one role
+// is only updated once.
+// Every Learner is updated even if an earlier one returns an error. The first error is returned.
+func (b *Bandit) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	var firstErr error
+	for _, l := range b.learners() {
+		if err := l.Observe(ctx, banditContext, arm, reward, propensity); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (b *Bandit) learners() []Learner {
+	var learners []Learner
+	for _, c := range []interface{}{b.RewardSource, b.Strategy, b.Sampler} {
+		l, ok := c.(Learner)
+		if !ok || containsLearner(learners, l) {
+			continue
+		}
+		learners = append(learners, l)
+	}
+	return learners
+}
+
+func containsLearner(learners []Learner, l Learner) bool {
+	if !reflect.TypeOf(l).Comparable() {
+		return false
+	}
+	for _, other := range learners {
+		if reflect.TypeOf(other) == reflect.TypeOf(l) && other == l {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package linear_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/linear"
+	"github.com/stitchfix/mab/numint"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestModel_GetRewards(t *testing.T) {
+	xs := [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}}
+	ys := []float64{1, 3, 5, 7}
+	lambda := 0.5
+
+	model := linear.NewModel(2, 2, linear.WithLambda(lambda), linear.WithSigma(2))
+
+	for i := range xs {
+		if err := model.Observe(context.Background(), xs[i], 1, ys[i], 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	x := []float64{1, 4}
+
+	rewards, err := model.GetRewards(context.Background(), x)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// batch ridge regression: A = lambda*I + X'X, theta = A^-1 X'y
+	a := mat.NewDense(2, 2, []float64{lambda, 0, 0, lambda})
+	X := mat.NewDense(4, 2, []float64{1, 0, 1, 1, 1, 2, 1, 3})
+	var xtx mat.Dense
+	xtx.Mul(X.T(), X)
+	a.Add(a, &xtx)
+
+	var aInv mat.Dense
+	if err := aInv.Inverse(a); err != nil {
+		t.Fatal(err)
+	}
+
+	var xty, theta, aInvX mat.VecDense
+	xty.MulVec(X.T(), mat.NewVecDense(4, ys))
+	theta.MulVec(&aInv, &xty)
+	xv := mat.NewVecDense(2, x)
+	aInvX.MulVec(&aInv, xv)
+
+	expected := mab.Normal(mat.Dot(&theta, xv), 2*math.Sqrt(mat.Dot(xv, &aInvX)))
+	actual := rewards[1].(mab.NormalDist)
+
+	if math.Abs(actual.Mu-expected.Mu) > 1e-9 || math.Abs(actual.Sigma-expected.Sigma) > 1e-9 {
+		t.Errorf("actual not %v. got=%v", expected, actual)
+	}
+
+	prior := rewards[0].(mab.NormalDist)
+	if prior.Mu != 0 || math.Abs(prior.Sigma-2*math.Sqrt(17/lambda)) > 1e-9 {
+		t.Errorf("unobserved arm should have prior predictive. got=%v", prior)
+	}
+}
+
+func TestModel_SelectArm(t *testing.T) {
+	model := linear.NewModel(2, 2)
+
+	for i := 0; i < 100; i++ {
+		for arm := 0; arm < 2; arm++ {
+			if err := model.Observe(context.Background(), []float64{1, 0}, arm, float64(1-arm), 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := model.Observe(context.Background(), []float64{0, 1}, arm, float64(arm), 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy mab.Strategy
+	}{
+		{"thompson", mab.NewThompson(numint.NewQuadrature())},
+		{"ucb", mab.NewUCB(1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := mab.Bandit{
+				RewardSource: model,
+				Strategy:     test.strategy,
+				Sampler:      mab.NewSha1Sampler(),
+			}
+
+			for arm, x := range [][]float64{{1, 0}, {0, 1}} {
+				result, err := b.SelectArm(context.Background(), "12345", x)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Probs[arm] < 0.99 {
+					t.Errorf("expected arm %d to have probability near 1. got=%v", arm, result.Probs)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_Error(t *testing.T) {
+	model := linear.NewModel(2, 3)
+
+	contexts := []interface{}{
+		nil,
+		"us",
+		[]float64{1, 2},
+		[]float64{1, 2, math.NaN()},
+		mat.NewVecDense(4, nil),
+	}
+
+	for _, c := range contexts {
+		if _, err := model.GetRewards(context.Background(), c); !errors.Is(err, mab.ErrInvalidContext) {
+			t.Errorf("expected ErrInvalidContext for context %v. got=%v", c, err)
+		}
+		if err := model.Observe(context.Background(), c, 0, 1, 1); !errors.Is(err, mab.ErrInvalidContext) {
+			t.Errorf("expected ErrInvalidContext for context %v. got=%v", c, err)
+		}
+	}
+
+	if err := model.Observe(context.Background(), []float64{1, 2, 3}, 2, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for arm out of range. got=%v", err)
+	}
+	if err := model.Observe(context.Background(), []float64{1, 2, 3}, 0, math.Inf(1), 1); !errors.Is(err, mab.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for infinite reward. got=%v", err)
+	}
+}
+
+func TestModel_OptionError(t *testing.T) {
+	for _, opt := range []linear.Option{linear.WithLambda(0), linear.WithLambda(-1), linear.WithSigma(0), linear.WithSigma(math.Inf(1))} {
+		model := linear.NewModel(2, 3, opt)
+		if _, err := model.GetRewards(context.Background(), []float64{1, 2, 3}); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter. got=%v", err)
+		}
+		if err := model.Observe(context.Background(), []float64{1, 2, 3}, 0, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter. got=%v", err)
+		}
+	}
+}
+
+func TestModel_SizeError(t *testing.T) {
+	for _, size := range [][2]int{{2, 0}, {2, -1}, {-1, 3}} {
+		model := linear.NewModel(size[0], size[1])
+		if _, err := model.GetRewards(context.Background(), []float64{1, 2, 3}); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for %d arms and dimension %d. got=%v", size[0], size[1], err)
+		}
+		if err := model.Observe(context.Background(), []float64{1, 2, 3}, 0, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for %d arms and dimension %d. got=%v", size[0], size[1], err)
+		}
+	}
+}
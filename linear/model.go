@@ -0,0 +1,157 @@
+// Package linear provides linear contextual reward models for use with mab bandits.
+package linear
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/internal/features"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultLambda = 1.0
+	defaultSigma  = 1.0
+)
+
+// NewModel returns a new Model for numArms arms and feature vectors of length dim, with any Option arguments applied.
+// If numArms is negative or dim is not positive, GetRewards and Observe return an ErrInvalidParameter error.
+// For linear Thompson sampling, combine the Model with the Thompson strategy:
+//	model := linear.NewModel(3, 5)
+//	bandit := mab.Bandit{
+//		RewardSource: model,
+//		Strategy:     mab.NewThompson(numint.NewQuadrature()),
+//		Sampler:      mab.NewSha1Sampler(),
+//	}
+// For LinUCB, use mab.NewUCB(alpha) as the Strategy instead.
+func NewModel(numArms, dim int, opts ...Option) *Model {
+	m := &Model{
+		numArms: numArms,
+		dim:     dim,
+		lambda:  defaultLambda,
+		sigma:   defaultSigma,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if numArms >= 0 && dim > 0 {
+		m.arms = make([]*armStats, numArms)
+		for i := range m.arms {
+			m.arms[i] = newArmStats(dim, m.lambda)
+		}
+	}
+	return m
+}
+
+// Model is a RewardSource that fits a separate ridge regression of reward on the feature vector for each arm.
+// The banditContext must be the feature vector, as a []float64 or a mat.Vector.
+// The reward estimate for each arm is the predictive posterior of the expected reward, which is a normal distribution
+// with mean theta'x and standard deviation sigma*sqrt(x'A^-1 x), where A = lambda*I + sum(x x') over the arm's
+// observations and theta is the ridge regression estimate.
+// The inverse of A is kept up to date with Sherman-Morrison rank-one updates, so that observing a reward and getting
+// rewards both take O(dim^2) time per arm.
+// A Model is safe for concurrent use.
+type Model struct {
+	numArms, dim  int
+	lambda, sigma float64
+
+	mu   sync.RWMutex
+	arms []*armStats
+}
+
+// armStats are the ridge regression statistics for a single arm.
+type armStats struct {
+	aInv  *mat.SymDense
+	b     *mat.VecDense
+	theta *mat.VecDense
+}
+
+func newArmStats(dim int, lambda float64) *armStats {
+	aInv := mat.NewSymDense(dim, nil)
+	for i := 0; i < dim; i++ {
+		aInv.SetSym(i, i, 1/lambda)
+	}
+	return &armStats{
+		aInv:  aInv,
+		b:     mat.NewVecDense(dim, nil),
+		theta: mat.NewVecDense(dim, nil),
+	}
+}
+
+// GetRewards returns the predictive posterior of the expected reward of each arm, given the feature vector.
+// Returns an error if lambda or sigma are not positive, or if the banditContext is not a feature vector of the right
+// length.
+func (m *Model) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	x, err := features.Vector(banditContext, m.dim)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rewards := make([]mab.Dist, len(m.arms))
+	for i, arm := range m.arms {
+		rewards[i] = mab.Normal(m.predict(arm, x))
+	}
+
+	return rewards, nil
+}
+
+// Observe updates the regression statistics for an arm with a reward observed for the given feature vector.
+// Returns an error if lambda or sigma are not positive, if the arm index is out of range, if the banditContext is not a
+// feature vector of the right length, or if the reward is not finite.
+func (m *Model) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	x, err := features.Observation(banditContext, m.dim, arm, len(m.arms))
+	if err != nil {
+		return err
+	}
+	if math.IsNaN(reward) || math.IsInf(reward, 0) {
+		return mab.Errorf(mab.ErrInvalidParameter, "reward must be finite. got=%f", reward)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.arms[arm].update(x, reward)
+	return nil
+}
+
+func (m *Model) validate() error {
+	if m.dim <= 0 {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid dimension: %d. Must be greater than 0", m.dim)
+	}
+	if m.numArms < 0 {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid number of arms: %d. Must be at least 0", m.numArms)
+	}
+	if !(m.lambda > 0) || math.IsInf(m.lambda, 1) {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid lambda value: %v. Must be finite and greater than 0", m.lambda)
+	}
+	if !(m.sigma > 0) || math.IsInf(m.sigma, 1) {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid sigma value: %v. Must be finite and greater than 0", m.sigma)
+	}
+	return nil
+}
+
+func (m *Model) predict(arm *armStats, x mat.Vector) (mean, stdDev float64) {
+	var ax mat.VecDense
+	ax.MulVec(arm.aInv, x)
+	return mat.Dot(arm.theta, x), m.sigma * math.Sqrt(math.Max(0, mat.Dot(x, &ax)))
+}
+
+func (a *armStats) update(x mat.Vector, reward float64) {
+	var u mat.VecDense
+	u.MulVec(a.aInv, x)
+	a.aInv.SymRankOne(a.aInv, -1/(1+mat.Dot(x, &u)), &u)
+
+	a.b.AddScaledVec(a.b, reward, x)
+	a.theta.MulVec(a.aInv, a.b)
+}
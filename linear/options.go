@@ -0,0 +1,20 @@
+package linear
+
+// Option is a function that can be passed to NewModel to override the default settings.
+type Option func(*Model)
+
+// WithLambda sets the ridge regularization strength, which is also the precision of the prior on the coefficients.
+// The default is 1.
+func WithLambda(lambda float64) Option {
+	return func(m *Model) {
+		m.lambda = lambda
+	}
+}
+
+// WithSigma sets the noise scale of the rewards, which scales the width of the predictive posterior.
+// Larger values lead to more exploration. The default is 1.
+func WithSigma(sigma float64) Option {
+	return func(m *Model) {
+		m.sigma = sigma
+	}
+}
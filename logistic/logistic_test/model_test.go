@@ -0,0 +1,170 @@
+package logistic_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/logistic"
+	"github.com/stitchfix/mab/numint"
+)
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func TestModel_Fit(t *testing.T) {
+	weights := [][]float64{{-2, 1}, {-1, -1}}
+
+	tests := []struct {
+		name string
+		opts []logistic.Option
+	}{
+		{"full covariance", nil},
+		{"diagonal", []logistic.Option{logistic.WithDiagonal()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := logistic.NewModel(2, 2, test.opts...)
+			rng := rand.New(rand.NewSource(1))
+
+			for i := 0; i < 20000; i++ {
+				x := []float64{1, rng.Float64()*2 - 1}
+				arm := i % 2
+				p := sigmoid(weights[arm][0]*x[0] + weights[arm][1]*x[1])
+				reward := 0.0
+				if rng.Float64() < p {
+					reward = 1
+				}
+				if err := model.Observe(context.Background(), x, arm, reward, 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			x := []float64{1, 0.5}
+			rewards, err := model.GetRewards(context.Background(), x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for arm, dist := range rewards {
+				expected := sigmoid(weights[arm][0]*x[0] + weights[arm][1]*x[1])
+				if math.Abs(dist.Mean()-expected) > 0.03 {
+					t.Errorf("arm %d mean not %f. got=%f", arm, expected, dist.Mean())
+				}
+				lo, hi := dist.Support()
+				if lo <= 0 || hi >= 1 || hi-lo > 0.2 {
+					t.Errorf("arm %d support not narrow. got=[%f, %f]", arm, lo, hi)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_SelectArm(t *testing.T) {
+	model := logistic.NewModel(3, 2)
+
+	for i := 0; i < 200; i++ {
+		for arm := 0; arm < 3; arm++ {
+			reward := 0.0
+			if arm == 1 && i%2 == 0 {
+				reward = 1
+			}
+			if err := model.Observe(context.Background(), []float64{1, 1}, arm, reward, 1); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	b := mab.Bandit{
+		RewardSource: model,
+		Strategy:     mab.NewThompson(numint.NewQuadrature()),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	result, err := b.SelectArm(context.Background(), "12345", []float64{1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := 0.0
+	for _, p := range result.Probs {
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-3 {
+		t.Errorf("probabilities should sum to 1. got=%v", result.Probs)
+	}
+	if result.Arm != 1 || result.Probs[1] < 0.99 {
+		t.Errorf("expected arm 1 to be selected with probability near 1. got=%d, %v", result.Arm, result.Probs)
+	}
+}
+
+func TestModel_Error(t *testing.T) {
+	model := logistic.NewModel(2, 2)
+
+	if _, err := model.GetRewards(context.Background(), "us"); !errors.Is(err, mab.ErrInvalidContext) {
+		t.Errorf("expected ErrInvalidContext for string context. got=%v", err)
+	}
+	if err := model.Observe(context.Background(), []float64{1}, 0, 1, 1); !errors.Is(err, mab.ErrInvalidContext) {
+		t.Errorf("expected ErrInvalidContext for short feature vector. got=%v", err)
+	}
+	if err := model.Observe(context.Background(), []float64{1, 2}, 2, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for arm out of range. got=%v", err)
+	}
+	if err := model.Observe(context.Background(), []float64{1, 2}, 0, 2, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+		t.Errorf("expected ErrInvalidParameter for reward above one. got=%v", err)
+	}
+}
+
+func TestModel_ZeroFeatures(t *testing.T) {
+	rewards, err := logistic.NewModel(2, 2).GetRewards(context.Background(), []float64{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rewards {
+		if _, ok := r.(mab.LogitNormalDist); !ok || math.Abs(r.Mean()-0.5) > 1e-9 {
+			t.Errorf("expected a logit-normal with mean 0.5 for a zero feature vector. got=%v", r)
+		}
+	}
+
+	bandit := mab.Bandit{
+		RewardSource: logistic.NewModel(2, 2),
+		Strategy:     mab.NewThompson(numint.NewQuadrature()),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+	res, err := bandit.SelectArm(context.Background(), "user1", []float64{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(res.Probs[0]-0.5) > 1e-3 || math.Abs(res.Probs[1]-0.5) > 1e-3 {
+		t.Errorf("expected equal probabilities for a zero feature vector. got=%v", res.Probs)
+	}
+}
+
+func TestModel_PriorVarianceError(t *testing.T) {
+	for _, v := range []float64{0, -1, math.Inf(1)} {
+		model := logistic.NewModel(2, 2, logistic.WithPriorVariance(v))
+		if _, err := model.GetRewards(context.Background(), []float64{1, 2}); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for prior variance %v. got=%v", v, err)
+		}
+		if err := model.Observe(context.Background(), []float64{1, 2}, 0, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for prior variance %v. got=%v", v, err)
+		}
+	}
+}
+
+func TestModel_SizeError(t *testing.T) {
+	for _, size := range [][2]int{{2, 0}, {2, -1}, {-1, 2}} {
+		model := logistic.NewModel(size[0], size[1])
+		if _, err := model.GetRewards(context.Background(), []float64{1, 2}); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for %d arms and dimension %d. got=%v", size[0], size[1], err)
+		}
+		if err := model.Observe(context.Background(), []float64{1, 2}, 0, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for %d arms and dimension %d. got=%v", size[0], size[1], err)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package logistic provides a Bayesian logistic regression reward model for contextual bandits with binary rewards.
+package logistic
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/internal/features"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultPriorVariance = 1.0
+
+	// minVariance is the smallest variance of w'x in a reward estimate. Strategies such as Thompson sampling need a
+	// continuous distribution, so a zero feature vector, for which the reward probability is known, still gets a
+	// LogitNormalDist rather than a Point.
+	minVariance = 1e-12
+)
+
+// NewModel returns a new Model for numArms arms and feature vectors of length dim, with any Option arguments applied.
+// If numArms is negative or dim is not positive, GetRewards and Observe return an ErrInvalidParameter error.
+// By default, each arm keeps a full covariance matrix. Use WithDiagonal for high-dimensional feature vectors.
+// For logistic Thompson sampling, combine the Model with the Thompson strategy:
+//	model := logistic.NewModel(3, 5)
+//	bandit := mab.Bandit{
+//		RewardSource: model,
+//		Strategy:     mab.NewThompson(numint.NewQuadrature()),
+//		Sampler:      mab.NewSha1Sampler(),
+//	}
+func NewModel(numArms, dim int, opts ...Option) *Model {
+	m := &Model{
+		numArms:       numArms,
+		dim:           dim,
+		priorVariance: defaultPriorVariance,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if numArms >= 0 && dim > 0 {
+		m.arms = make([]*armStats, numArms)
+		for i := range m.arms {
+			m.arms[i] = newArmStats(dim, m.priorVariance, m.diagonal)
+		}
+	}
+	return m
+}
+
+// Model is a RewardSource that fits a Bayesian logistic regression of a binary reward on the feature vector for each arm.
+// The banditContext must be the feature vector, as a []float64 or a mat.Vector.
+// The posterior of the coefficients is approximated by a Gaussian (the Laplace approximation), which is updated online
+// with a single Newton step for each observed reward.
+// The reward estimate for each arm is the predictive distribution of the reward probability, sigmoid(w'x), which is
+// a LogitNormalDist on the interval (0, 1).
+// A Model is safe for concurrent use.
+type Model struct {
+	numArms, dim  int
+	priorVariance float64
+	diagonal      bool
+
+	mu   sync.RWMutex
+	arms []*armStats
+}
+
+// armStats are the mean and covariance of the Gaussian approximation to the posterior of one arm's coefficients.
+// If diagonal is true, only the diagonal of the covariance is used, and it is stored in variances.
+type armStats struct {
+	mean      *mat.VecDense
+	cov       *mat.SymDense
+	variances []float64
+	diagonal  bool
+}
+
+func newArmStats(dim int, priorVariance float64, diagonal bool) *armStats {
+	a := &armStats{
+		mean:     mat.NewVecDense(dim, nil),
+		diagonal: diagonal,
+	}
+	if diagonal {
+		a.variances = make([]float64, dim)
+		for i := range a.variances {
+			a.variances[i] = priorVariance
+		}
+		return a
+	}
+	a.cov = mat.NewSymDense(dim, nil)
+	for i := 0; i < dim; i++ {
+		a.cov.SetSym(i, i, priorVariance)
+	}
+	return a
+}
+
+// GetRewards returns the predictive distribution of the reward probability of each arm, given the feature vector.
+// If the feature vector is zero, the reward probability is known, and it is returned as a LogitNormalDist with a
+// negligible variance, so that it can be used with Thompson sampling.
+// Returns an error if the prior variance is not positive, or if the banditContext is not a feature vector of the right
+// length.
+func (m *Model) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	x, err := features.Vector(banditContext, m.dim)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rewards := make([]mab.Dist, len(m.arms))
+	for i, arm := range m.arms {
+		mean, variance := arm.predict(x)
+		rewards[i] = mab.LogitNormal(mean, math.Sqrt(math.Max(variance, minVariance)))
+	}
+
+	return rewards, nil
+}
+
+// Observe updates the posterior of an arm with a reward observed for the given feature vector.
+// The reward should be 0 or 1, but fractional rewards between 0 and 1 are accepted.
+// Returns an error if the prior variance is not positive, if the arm index is out of range, if the banditContext is not
+// a feature vector of the right length, or if the reward is not between 0 and 1.
+func (m *Model) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	x, err := features.Observation(banditContext, m.dim, arm, len(m.arms))
+	if err != nil {
+		return err
+	}
+	if !(reward >= 0 && reward <= 1) {
+		return mab.Errorf(mab.ErrInvalidParameter, "reward must be between 0 and 1. got=%f", reward)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.arms[arm].update(x, reward)
+	return nil
+}
+
+func (m *Model) validate() error {
+	if m.dim <= 0 {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid dimension: %d. Must be greater than 0", m.dim)
+	}
+	if m.numArms < 0 {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid number of arms: %d. Must be at least 0", m.numArms)
+	}
+	if !(m.priorVariance > 0) || math.IsInf(m.priorVariance, 1) {
+		return mab.Errorf(mab.ErrInvalidParameter, "invalid prior variance: %v. Must be finite and greater than 0", m.priorVariance)
+	}
+	return nil
+}
+
+// predict returns the mean and variance of w'x under the posterior.
+func (a *armStats) predict(x mat.Vector) (mean, variance float64) {
+	mean = mat.Dot(a.mean, x)
+	if a.diagonal {
+		for i, v := range a.variances {
+			variance += v * x.AtVec(i) * x.AtVec(i)
+		}
+		return mean, variance
+	}
+	var sx mat.VecDense
+	sx.MulVec(a.cov, x)
+	return mean, math.Max(0, mat.Dot(x, &sx))
+}
+
+// update takes a single Newton step from the current mean, adding the curvature of the log-likelihood of the new
+// observation to the posterior precision.
+func (a *armStats) update(x mat.Vector, reward float64) {
+	p := sigmoid(mat.Dot(a.mean, x))
+	curvature := p * (1 - p)
+
+	if a.diagonal {
+		for i := range a.variances {
+			xi := x.AtVec(i)
+			a.variances[i] = 1 / (1/a.variances[i] + curvature*xi*xi)
+			a.mean.SetVec(i, a.mean.AtVec(i)+a.variances[i]*xi*(reward-p))
+		}
+		return
+	}
+
+	// Sherman-Morrison update of the covariance for the rank-one increase in precision.
+	var sx mat.VecDense
+	sx.MulVec(a.cov, x)
+	a.cov.SymRankOne(a.cov, -curvature/(1+curvature*mat.Dot(x, &sx)), &sx)
+
+	var step mat.VecDense
+	step.MulVec(a.cov, x)
+	a.mean.AddScaledVec(a.mean, reward-p, &step)
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
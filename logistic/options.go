@@ -0,0 +1,19 @@
+package logistic
+
+// Option is a function that can be passed to NewModel to override the default settings.
+type Option func(*Model)
+
+// WithPriorVariance sets the variance of the zero-mean Gaussian prior on each coefficient. The default is 1.
+func WithPriorVariance(v float64) Option {
+	return func(m *Model) {
+		m.priorVariance = v
+	}
+}
+
+// WithDiagonal approximates the posterior covariance of each arm with its diagonal.
+// This reduces the cost of an update from O(dim^2) to O(dim), at the cost of ignoring correlations between features.
+func WithDiagonal() Option {
+	return func(m *Model) {
+		m.diagonal = true
+	}
+}
@@ -0,0 +1,98 @@
+package mab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandit_SelectArm_Filters(t *testing.T) {
+	rewards := []mab.Dist{mab.Point(0.9), mab.Point(0.5), mab.Point(0.7), mab.Point(0.1)}
+
+	legal := mab.ArmFilterFunc(func(ctx context.Context, unit string, banditContext interface{}, numArms int) ([]mab.FilteredArm, error) {
+		if banditContext == "restricted" {
+			return []mab.FilteredArm{{Arm: 0, Reason: "legal"}, {Arm: 2, Reason: "legal"}}, nil
+		}
+		return nil, nil
+	})
+
+	b := mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: rewards},
+		Strategy:     mab.NewEpsilonGreedy(0.2),
+		Sampler:      mab.NewSha1Sampler(),
+		Filters:      []mab.ArmFilter{mab.EligibilityMask("out of stock", true, true, false), legal},
+	}
+
+	res, err := b.SelectArm(context.Background(), "12345", "restricted")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []mab.FilteredArm{{Arm: 2, Reason: "out of stock"}, {Arm: 0, Reason: "legal"}}, res.Filtered)
+	assert.Equal(t, []mab.Dist{mab.Null(), mab.Point(0.5), mab.Null(), mab.Point(0.1)}, res.Rewards)
+	assert.InDeltaSlice(t, []float64{0, 0.9, 0, 0.1}, res.Probs, 1e-9)
+	assert.Contains(t, []int{1, 3}, res.Arm)
+
+	// the reward source's estimates are not modified
+	assert.Equal(t, mab.Point(0.9), rewards[0])
+
+	res, err = b.SelectArm(context.Background(), "12345", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []mab.FilteredArm{{Arm: 2, Reason: "out of stock"}}, res.Filtered)
+	assert.InDeltaSlice(t, []float64{0.2/3 + 0.8, 0.2 / 3, 0, 0.2 / 3}, res.Probs, 1e-9)
+}
+
+func TestBandit_SelectArm_FilterErrors(t *testing.T) {
+	tests := map[string]mab.ArmFilter{
+		"filter error": mab.ArmFilterFunc(func(context.Context, string, interface{}, int) ([]mab.FilteredArm, error) {
+			return nil, errors.New("inventory service unavailable")
+		}),
+		"out of range": mab.ArmFilterFunc(func(context.Context, string, interface{}, int) ([]mab.FilteredArm, error) {
+			return []mab.FilteredArm{{Arm: 5, Reason: "bad"}}, nil
+		}),
+	}
+
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0.5), mab.Point(0.6)}},
+				Strategy:     mab.NewEpsilonGreedy(0.1),
+				Sampler:      mab.NewSha1Sampler(),
+				Filters:      []mab.ArmFilter{filter},
+			}
+
+			res, err := b.SelectArm(context.Background(), "12345", nil)
+			assert.Error(t, err)
+			assert.Equal(t, -1, res.Arm)
+			assert.Len(t, res.Rewards, 2)
+			assert.Empty(t, res.Probs)
+		})
+	}
+}
+
+func TestResult_JSON_Filtered(t *testing.T) {
+	res := mab.Result{
+		DecisionID: "abc",
+		Rewards:    []mab.Dist{mab.Null(), mab.Beta(2, 3)},
+		Probs:      []float64{0, 1},
+		Arm:        1,
+		Filtered:   []mab.FilteredArm{{Arm: 0, Reason: "legal"}},
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded mab.Result
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, res, decoded)
+}
@@ -0,0 +1,178 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBayesianSource_Beta(t *testing.T) {
+	source := mab.NewBayesianSource(3, mab.BetaPrior(1, 1), mab.WithArmPrior(2, mab.BetaPrior(2, 8)))
+
+	observations := []struct {
+		banditContext interface{}
+		arm           int
+		reward        float64
+	}{
+		{"us", 0, 1},
+		{"us", 0, 0},
+		{"us", 0, 1},
+		{"us", 1, 0},
+		{"uk", 1, 1},
+		{"uk", 2, 0.5},
+	}
+
+	for _, o := range observations {
+		if err := source.Observe(context.Background(), o.banditContext, o.arm, o.reward, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		banditContext interface{}
+		expected      []mab.Dist
+	}{
+		{"us", []mab.Dist{mab.Beta(3, 2), mab.Beta(1, 2), mab.Beta(2, 8)}},
+		{"uk", []mab.Dist{mab.Beta(1, 1), mab.Beta(2, 1), mab.Beta(2.5, 8.5)}},
+		{"fr", []mab.Dist{mab.Beta(1, 1), mab.Beta(1, 1), mab.Beta(2, 8)}},
+		{nil, []mab.Dist{mab.Beta(1, 1), mab.Beta(1, 1), mab.Beta(2, 8)}},
+	}
+
+	for _, test := range tests {
+		actual, err := source.GetRewards(context.Background(), test.banditContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !assert.ObjectsAreEqualValues(test.expected, actual) {
+			t.Errorf("%v: actual not %v. got=%v", test.banditContext, test.expected, actual)
+		}
+	}
+}
+
+func TestBayesianSource_Normal(t *testing.T) {
+	source := mab.NewBayesianSource(1, mab.NormalPrior(0, 1, 1))
+
+	for _, r := range []float64{1, 2, 3} {
+		if err := source.Observe(context.Background(), nil, 0, r, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rewards, err := source.GetRewards(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// prior precision 1 plus three observations with precision 1
+	expected := mab.Normal(1.5, 0.5)
+	actual := rewards[0].(mab.NormalDist)
+
+	if math.Abs(actual.Mu-expected.Mu) > 1e-9 || math.Abs(actual.Sigma-expected.Sigma) > 1e-9 {
+		t.Errorf("actual not %v. got=%v", expected, actual)
+	}
+}
+
+func TestBayesianSource_NormalInverseGamma(t *testing.T) {
+	source := mab.NewBayesianSource(1, mab.NormalInverseGammaPrior(0, 1, 1, 1))
+
+	for _, r := range []float64{1, 2, 3} {
+		if err := source.Observe(context.Background(), nil, 0, r, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rewards, err := source.GetRewards(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mu = 6/4, kappa = 4, alpha = 2.5, beta = 1 + 2/2 + 1*3*(2-0)^2/(2*4) = 3.5
+	expected := mab.Normal(1.5, math.Sqrt(3.5/(2.5*4)))
+	actual := rewards[0].(mab.NormalDist)
+
+	if math.Abs(actual.Mu-expected.Mu) > 1e-9 || math.Abs(actual.Sigma-expected.Sigma) > 1e-9 {
+		t.Errorf("actual not %v. got=%v", expected, actual)
+	}
+}
+
+func TestBayesianSource_ObserveError(t *testing.T) {
+	tests := []struct {
+		name          string
+		prior         mab.Prior
+		banditContext interface{}
+		arm           int
+		reward        float64
+		kind          error
+	}{
+		{"negative arm", mab.BetaPrior(1, 1), nil, -1, 1, mab.ErrInvalidParameter},
+		{"arm out of range", mab.BetaPrior(1, 1), nil, 2, 1, mab.ErrInvalidParameter},
+		{"beta reward above one", mab.BetaPrior(1, 1), nil, 0, 2, mab.ErrInvalidParameter},
+		{"beta reward NaN", mab.BetaPrior(1, 1), nil, 0, math.NaN(), mab.ErrInvalidParameter},
+		{"normal reward infinite", mab.NormalPrior(0, 1, 1), nil, 0, math.Inf(1), mab.ErrInvalidParameter},
+		{"normal zero noise", mab.NormalPrior(0, 1, 0), nil, 0, 1, mab.ErrInvalidParameter},
+		{"uncomparable context", mab.BetaPrior(1, 1), []string{"us"}, 0, 1, mab.ErrInvalidContext},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := mab.NewBayesianSource(2, test.prior)
+			if err := source.Observe(context.Background(), test.banditContext, test.arm, test.reward, 1); !errors.Is(err, test.kind) {
+				t.Errorf("expected %v. got=%v", test.kind, err)
+			}
+		})
+	}
+}
+
+func TestBayesianSource_InvalidPrior(t *testing.T) {
+	priors := map[string]mab.Prior{
+		"beta zero alpha":          mab.BetaPrior(0, 1),
+		"beta infinite beta":       mab.BetaPrior(1, math.Inf(1)),
+		"normal zero sigma":        mab.NormalPrior(0, 0, 1),
+		"normal NaN mu":            mab.NormalPrior(math.NaN(), 1, 1),
+		"normal zero noise":        mab.NormalPrior(0, 1, 0),
+		"nig zero kappa":           mab.NormalInverseGammaPrior(0, 0, 1, 1),
+		"nig zero alpha":           mab.NormalInverseGammaPrior(0, 1, 0, 1),
+		"nig negative beta":        mab.NormalInverseGammaPrior(0, 1, 1, -1),
+		"discounted invalid prior": mab.DiscountedPrior(mab.BetaPrior(-1, 1), 0.9),
+		"discounted invalid gamma": mab.DiscountedPrior(mab.BetaPrior(1, 1), 0),
+		"window invalid prior":     mab.CountWindowPrior(mab.NormalPrior(0, -1, 1), 10),
+	}
+
+	for name, prior := range priors {
+		t.Run(name, func(t *testing.T) {
+			source := mab.NewBayesianSource(2, mab.BetaPrior(1, 1), mab.WithArmPrior(1, prior))
+			if _, err := source.GetRewards(context.Background(), nil); !errors.Is(err, mab.ErrInvalidParameter) {
+				t.Errorf("expected ErrInvalidParameter from GetRewards. got=%v", err)
+			}
+			if err := source.Observe(context.Background(), nil, 0, 1, 1); !errors.Is(err, mab.ErrInvalidParameter) {
+				t.Errorf("expected ErrInvalidParameter from Observe. got=%v", err)
+			}
+		})
+	}
+}
+
+func TestBayesianSource_WithContextKey(t *testing.T) {
+	key := func(banditContext interface{}) (interface{}, error) {
+		return banditContext.(map[string]string)["country"], nil
+	}
+
+	source := mab.NewBayesianSource(1, mab.BetaPrior(1, 1), mab.WithContextKey(key))
+
+	if err := source.Observe(context.Background(), map[string]string{"country": "us", "device": "ios"}, 0, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := source.GetRewards(context.Background(), map[string]string{"country": "us", "device": "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []mab.Dist{mab.Beta(2, 1)}
+	if !assert.ObjectsAreEqualValues(expected, actual) {
+		t.Errorf("actual not %v. got=%v", expected, actual)
+	}
+}
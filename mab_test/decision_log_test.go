@@ -0,0 +1,254 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandit_Logger(t *testing.T) {
+	var decisions []mab.Decision
+
+	b := mab.Bandit{
+		RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+			"us": {mab.Point(0.2), mab.Point(0.8)},
+		}},
+		Strategy: mab.NewEpsilonGreedy(0.2),
+		Sampler:  mab.NewSha1Sampler(),
+		Logger: mab.DecisionLoggerFunc(func(ctx context.Context, d mab.Decision) {
+			decisions = append(decisions, d)
+		}),
+	}
+
+	result, err := b.SelectArm(context.Background(), "12345", "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = b.SelectArm(context.Background(), "12345", "fr")
+	if err == nil {
+		t.Fatal("expected error but didn't get one")
+	}
+
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions. got=%d", len(decisions))
+	}
+
+	if result.DecisionID == "" || decisions[0].Result.DecisionID != result.DecisionID {
+		t.Errorf("decision ID not %q. got=%q", result.DecisionID, decisions[0].Result.DecisionID)
+	}
+	if decisions[0].Unit != "12345" || decisions[0].BanditContext != "us" || decisions[0].Error != "" {
+		t.Errorf("unexpected decision: %+v", decisions[0])
+	}
+	if decisions[0].Propensity() != result.Probs[result.Arm] {
+		t.Errorf("propensity not %f. got=%f", result.Probs[result.Arm], decisions[0].Propensity())
+	}
+
+	if decisions[1].Error == "" || decisions[1].Result.Arm != -1 || decisions[1].Propensity() != 0 {
+		t.Errorf("expected failed decision to be logged. got=%+v", decisions[1])
+	}
+	if decisions[1].Result.DecisionID == result.DecisionID {
+		t.Error("decision IDs should be unique")
+	}
+}
+
+func TestJSONLLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "decisions.jsonl")
+
+	var errs []error
+	logger, err := mab.NewJSONLLogger(path, mab.WithMaxBytes(1000), mab.WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Beta(10, 20), mab.Normal(0.5, 0.1), mab.Null()}},
+		Strategy:     mab.NewEpsilonGreedy(0.1),
+		Sampler:      mab.NewSha1Sampler(),
+		Logger:       logger,
+	}
+
+	var expected []mab.Result
+	for _, unit := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		result, err := b.SelectArm(context.Background(), unit, map[string]interface{}{"country": "us"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = append(expected, result)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) < 3 {
+		t.Errorf("expected log to be rotated. got files=%v", files)
+	}
+
+	var actual []mab.Result
+	for _, f := range files {
+		err := mab.ReadDecisions(f, func(d mab.Decision) error {
+			info, err := os.Stat(f)
+			if err != nil {
+				return err
+			}
+			if info.Size() > 1000 {
+				t.Errorf("file %s larger than max bytes. got=%d", f, info.Size())
+			}
+			if d.BanditContext.(map[string]interface{})["country"] != "us" {
+				t.Errorf("context not logged. got=%v", d.BanditContext)
+			}
+			actual = append(actual, d.Result)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.ElementsMatch(t, expected, actual)
+
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	b.SelectArm(context.Background(), "k", nil)
+	if len(errs) != 1 {
+		t.Errorf("expected error after close. got=%v", errs)
+	}
+}
+
+func TestJSONLLogger_NonFiniteProbs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "decisions.jsonl")
+	logger, err := mab.NewJSONLLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probs := []float64{math.NaN(), math.Inf(1), 0.5}
+	logger.LogDecision(context.Background(), mab.Decision{Result: mab.Result{DecisionID: "a", Probs: probs, Arm: -1}})
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []mab.Decision
+	err = mab.ReadDecisions(path, func(d mab.Decision) error {
+		logged = append(logged, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 1 || !assert.ObjectsAreEqual([]float64{0, 0, 0.5}, logged[0].Result.Probs) {
+		t.Errorf("expected the decision with non-finite probabilities logged as 0. got=%+v", logged)
+	}
+	if !math.IsNaN(probs[0]) {
+		t.Error("logging modified the decision's probabilities")
+	}
+}
+
+func TestJSONLLogger_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(sub, "decisions.jsonl")
+
+	// the clock is read while rotating, so it can remove the directory to make the rotation fail
+	removeDir := make(chan struct{}, 1)
+	clock := mab.ClockFunc(func() time.Time {
+		select {
+		case <-removeDir:
+			os.RemoveAll(sub)
+		default:
+		}
+		return time.Now()
+	})
+
+	errs := make(chan error, 10)
+	logger, err := mab.NewJSONLLogger(path, mab.WithMaxBytes(1), mab.WithLogClock(clock), mab.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.LogDecision(context.Background(), mab.Decision{Result: mab.Result{DecisionID: "a", Arm: -1}})
+	removeDir <- struct{}{}
+	logger.LogDecision(context.Background(), mab.Decision{Result: mab.Result{DecisionID: "b", Arm: -1}})
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected an error for the failed rotation")
+	}
+
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logger.LogDecision(context.Background(), mab.Decision{Result: mab.Result{DecisionID: "c", Arm: -1}})
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	err = mab.ReadDecisions(path, func(d mab.Decision) error {
+		ids = append(ids, d.Result.DecisionID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "c" {
+		t.Errorf("expected decision c in the reopened log. got=%v", ids)
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors after reopening: %v", <-errs)
+	}
+}
+
+func TestJSONLLogger_BufferSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, n := range []int{0, -1} {
+		if _, err := mab.NewJSONLLogger(filepath.Join(dir, "decisions.jsonl"), mab.WithBufferSize(n)); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter for buffer size %d. got=%v", n, err)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package mab
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalDist(t *testing.T) {
+	tests := []struct {
+		name     string
+		dist     mab.Dist
+		expected string
+	}{
+		{
+			"beta",
+			mab.Beta(10, 20),
+			`{"type":"beta","alpha":10,"beta":20}`,
+		},
+		{
+			"normal",
+			mab.Normal(-1.5, 0.25),
+			`{"type":"normal","mu":-1.5,"sigma":0.25}`,
+		},
+		{
+			"point",
+			mab.Point(0.5),
+			`{"type":"point","mu":0.5}`,
+		},
+		{
+			"point at zero",
+			mab.Point(0),
+			`{"type":"point","mu":0}`,
+		},
+		{
+			"null",
+			mab.Null(),
+			`{"type":"null"}`,
+		},
+		{
+			"sampled",
+			mab.WithSampleSize(mab.Beta(10, 20), 28),
+			`{"type":"sampled","dist":{"type":"beta","alpha":10,"beta":20},"n":28}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := mab.MarshalDist(test.dist)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != test.expected {
+				t.Errorf("actual not %s. got=%s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDistsFromJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected []mab.Dist
+	}{
+		{
+			"no arms",
+			[]byte(`[]`),
+			[]mab.Dist{},
+		},
+		{
+			"one arm",
+			[]byte(`[{"type": "beta", "alpha": 10, "beta": 20}]`),
+			[]mab.Dist{mab.Beta(10, 20)},
+		},
+		{
+			"mixed families",
+			[]byte(`[{"type": "beta", "alpha": 10, "beta": 20}, {"type": "normal", "mu": 1.5, "sigma": 2}, {"type": "point", "mu": -3}, {"type": "null"}]`),
+			[]mab.Dist{mab.Beta(10, 20), mab.Normal(1.5, 2), mab.Point(-3), mab.Null()},
+		},
+		{
+			"extra keys",
+			[]byte(`[{"type": "normal", "mu": 1.5, "sigma": 2, "count": 100}, {"type": "null", "mu": 4}]`),
+			[]mab.Dist{mab.Normal(1.5, 2), mab.Null()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := mab.DistsFromJSON(test.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !assert.ObjectsAreEqualValues(test.expected, actual) {
+				t.Errorf("actual not %v. got=%v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDistsFromJSONError(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			"empty response",
+			[]byte(``),
+		},
+		{
+			"not an array",
+			[]byte(`{"type": "beta", "alpha": 1, "beta": 2}`),
+		},
+		{
+			"missing type",
+			[]byte(`[{"type": "beta", "alpha": 1, "beta": 2}, {"alpha": 1, "beta": 2}]`),
+		},
+		{
+			"unknown type",
+			[]byte(`[{"type": "gamma", "k": 1, "theta": 2}]`),
+		},
+		{
+			"missing alpha",
+			[]byte(`[{"type": "beta", "beta": 2}]`),
+		},
+		{
+			"zero beta",
+			[]byte(`[{"type": "beta", "alpha": 1, "beta": 0}]`),
+		},
+		{
+			"missing sigma",
+			[]byte(`[{"type": "normal", "mu": 2}]`),
+		},
+		{
+			"negative sigma",
+			[]byte(`[{"type": "normal", "mu": 2, "sigma": -1}]`),
+		},
+		{
+			"missing mu",
+			[]byte(`[{"type": "point"}]`),
+		},
+		{
+			"logit-normal zero sigma",
+			[]byte(`[{"type": "logit_normal", "mu": -2, "sigma": 0}]`),
+		},
+		{
+			"sampled missing n",
+			[]byte(`[{"type": "sampled", "dist": {"type": "beta", "alpha": 1, "beta": 2}}]`),
+		},
+		{
+			"sampled invalid dist",
+			[]byte(`[{"type": "sampled", "dist": {"type": "beta", "alpha": 0, "beta": 2}, "n": 1}]`),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := mab.DistsFromJSON(test.data)
+			if err == nil {
+				t.Error("expected error but didn't get one")
+			}
+		})
+	}
+}
+
+func TestDistJSONRoundTrip(t *testing.T) {
+	dists := []mab.Dist{mab.Beta(1.945, 10), mab.Normal(-0.3, 0.01), mab.LogitNormal(-2.1, 0.3), mab.Point(42), mab.Null(), mab.WithSampleSize(mab.Normal(1, 2), 3)}
+
+	data, err := mab.MarshalDists(dists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := mab.DistsFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.ObjectsAreEqualValues(dists, actual) {
+		t.Errorf("actual not %v. got=%v", dists, actual)
+	}
+}
+
+func TestResultJSONRoundTrip(t *testing.T) {
+	result := mab.Result{
+		Rewards: []mab.Dist{mab.Beta(40, 474), mab.Normal(0.1, 0.02), mab.Null()},
+		Probs:   []float64{0.25, 0.75, 0},
+		Arm:     1,
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual mab.Result
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.ObjectsAreEqualValues(result, actual) {
+		t.Errorf("actual not %v. got=%v", result, actual)
+	}
+}
+
+type uniformDist struct {
+	A, B float64
+}
+
+func (u uniformDist) CDF(x float64) float64 {
+	return math.Min(1, math.Max(0, (x-u.A)/(u.B-u.A)))
+}
+
+func (u uniformDist) Mean() float64 { return (u.A + u.B) / 2 }
+
+func (u uniformDist) Prob(x float64) float64 {
+	if x < u.A || x > u.B {
+		return 0
+	}
+	return 1 / (u.B - u.A)
+}
+
+func (u uniformDist) Rand() float64 { return u.Mean() }
+
+func (u uniformDist) Support() (float64, float64) { return u.A, u.B }
+
+func (u uniformDist) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"type":"test_uniform","a":%v,"b":%v}`, u.A, u.B)), nil
+}
+
+func TestRegisterDist(t *testing.T) {
+	mab.RegisterDist("test_uniform", mab.DistDecoderFunc(func(data []byte) (mab.Dist, error) {
+		var u uniformDist
+		err := json.Unmarshal(data, &struct {
+			A *float64 `json:"a"`
+			B *float64 `json:"b"`
+		}{&u.A, &u.B})
+		return u, err
+	}))
+
+	dists := []mab.Dist{uniformDist{0, 2}, mab.Beta(2, 3)}
+
+	data, err := mab.MarshalDists(dists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := mab.DistsFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.ObjectsAreEqualValues(dists, actual) {
+		t.Errorf("actual not %v. got=%v", dists, actual)
+	}
+}
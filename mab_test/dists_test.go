@@ -0,0 +1,45 @@
+package mab
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/numint"
+)
+
+func TestLogitNormal(t *testing.T) {
+	dists := []mab.LogitNormalDist{
+		mab.LogitNormal(0, 1),
+		mab.LogitNormal(-2, 0.5),
+		mab.LogitNormal(3, 0.1),
+	}
+
+	q := numint.NewQuadrature()
+
+	for _, d := range dists {
+		t.Run(d.String(), func(t *testing.T) {
+			a, b := d.Support()
+
+			total, err := q.Integrate(d.Prob, a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(total-(d.CDF(b)-d.CDF(a))) > 1e-4 {
+				t.Errorf("integral of Prob not %f. got=%f", d.CDF(b)-d.CDF(a), total)
+			}
+
+			mean, err := q.Integrate(func(x float64) float64 { return x * d.Prob(x) }, 0, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(mean-d.Mean()) > 0.01 {
+				t.Errorf("mean not %f. got=%f", mean, d.Mean())
+			}
+
+			if d.CDF(0) != 0 || d.CDF(1) != 1 || d.Prob(0) != 0 || d.Prob(1) != 0 {
+				t.Error("distribution must be zero outside of (0, 1)")
+			}
+		})
+	}
+}
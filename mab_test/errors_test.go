@@ -0,0 +1,147 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/numint"
+)
+
+func TestSelectArm_ErrorKinds(t *testing.T) {
+	respond := func(status int, body string) mab.HttpDoer {
+		return doerFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		})
+	}
+
+	tests := []struct {
+		name   string
+		bandit *mab.Bandit
+		kind   error
+		stage  mab.Stage
+	}{
+		{
+			"reward fetch",
+			&mab.Bandit{
+				RewardSource: mab.NewHTTPSource(respond(503, "overloaded"), "http://rewards", mab.ParseFunc(mab.BetaFromJSON)),
+			},
+			mab.ErrRewardFetch,
+			mab.StageRewards,
+		},
+		{
+			"reward parse",
+			&mab.Bandit{
+				RewardSource: mab.NewHTTPSource(respond(200, "not json"), "http://rewards", mab.ParseFunc(mab.BetaFromJSON)),
+			},
+			mab.ErrRewardParse,
+			mab.StageRewards,
+		},
+		{
+			"invalid dist",
+			&mab.Bandit{
+				RewardSource: mab.NewHTTPSource(respond(200, `[{"alpha": -1, "beta": 1}]`), "http://rewards", mab.ParseFunc(mab.BetaFromJSON)),
+			},
+			mab.ErrInvalidDist,
+			mab.StageRewards,
+		},
+		{
+			"invalid context",
+			&mab.Bandit{
+				RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{}},
+			},
+			mab.ErrInvalidContext,
+			mab.StageRewards,
+		},
+		{
+			"invalid parameter",
+			&mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(1)}},
+				Strategy:     mab.NewEpsilonGreedy(2),
+			},
+			mab.ErrInvalidParameter,
+			mab.StageProbs,
+		},
+		{
+			"not converged",
+			&mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Normal(0, 1), mab.Normal(0.1, 1)}},
+				Strategy:     mab.NewThompson(numint.NewQuadrature(numint.WithMaxIter(1), numint.WithAbsTol(1e-300), numint.WithRelTol(0))),
+			},
+			mab.ErrNotConverged,
+			mab.StageProbs,
+		},
+		{
+			"invalid probs",
+			&mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(1)}},
+				Strategy:     strategyFunc(func([]mab.Dist) ([]float64, error) { return []float64{-1}, nil }),
+			},
+			mab.ErrInvalidProbs,
+			mab.StageSample,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := test.bandit
+			b.Name = "test"
+			if b.Strategy == nil {
+				b.Strategy = mab.NewEpsilonGreedy(0.1)
+			}
+			b.Sampler = mab.NewSha1Sampler()
+
+			_, err := b.SelectArm(context.Background(), "12345", "us")
+			if !errors.Is(err, test.kind) {
+				t.Fatalf("expected %v. got=%v", test.kind, err)
+			}
+			if kind := mab.ErrorKind(err); kind != test.kind {
+				t.Errorf("expected kind %v. got=%v", test.kind, kind)
+			}
+
+			var selectErr *mab.SelectError
+			if !errors.As(err, &selectErr) {
+				t.Fatalf("expected a SelectError. got=%T", err)
+			}
+			if selectErr.Stage != test.stage || selectErr.Bandit != "test" {
+				t.Errorf("unexpected SelectError: %+v", selectErr)
+			}
+		})
+	}
+}
+
+func TestErrorf(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := mab.Errorf(mab.ErrRewardFetch, "get rewards: %w", cause)
+
+	if err.Error() != "get rewards: connection refused" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	if !errors.Is(err, mab.ErrRewardFetch) || !errors.Is(err, cause) {
+		t.Errorf("expected error to match its kind and cause: %v", err)
+	}
+	if errors.Is(err, mab.ErrRewardParse) {
+		t.Error("error matched the wrong kind")
+	}
+
+	if mab.ErrorKind(errors.New("unclassified")) != nil {
+		t.Error("expected nil kind for an unclassified error")
+	}
+
+	timeout := mab.Errorf(mab.ErrRewardFetch, "%w", context.DeadlineExceeded)
+	if !errors.Is(timeout, context.DeadlineExceeded) {
+		t.Error("expected timeout to match context.DeadlineExceeded")
+	}
+}
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+type strategyFunc func([]mab.Dist) ([]float64, error)
+
+func (f strategyFunc) ComputeProbs(rewards []mab.Dist) ([]float64, error) { return f(rewards) }
@@ -0,0 +1,133 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoldout_Contains(t *testing.T) {
+	small := &mab.Holdout{Fraction: 0.05, Salt: "holdout-2020"}
+	large := &mab.Holdout{Fraction: 0.2, Salt: "holdout-2020"}
+	otherSalt := &mab.Holdout{Fraction: 0.2, Salt: "holdout-2021"}
+
+	n := 20000
+	inSmall, inLarge, inBoth := 0, 0, 0
+	for i := 0; i < n; i++ {
+		unit := fmt.Sprintf("user-%d", i)
+		if small.Contains(unit) {
+			inSmall++
+			assert.True(t, large.Contains(unit), "increasing the fraction should only add units")
+		}
+		if large.Contains(unit) {
+			inLarge++
+			if otherSalt.Contains(unit) {
+				inBoth++
+			}
+		}
+		assert.Equal(t, small.Contains(unit), small.Contains(unit))
+	}
+
+	assert.InDelta(t, 0.05, float64(inSmall)/float64(n), 0.01)
+	assert.InDelta(t, 0.2, float64(inLarge)/float64(n), 0.01)
+	// membership under a different salt is independent
+	assert.InDelta(t, 0.2, float64(inBoth)/float64(inLarge), 0.03)
+}
+
+func TestBandit_SelectArm_Holdout(t *testing.T) {
+	rewards := []mab.Dist{mab.Point(0.1), mab.Point(0.9), mab.Null(), mab.Point(0.5)}
+
+	t.Run("control arm", func(t *testing.T) {
+		source := &countingSource{RewardStub: mab.RewardStub{Rewards: rewards}}
+		b := mab.Bandit{
+			RewardSource: source,
+			Strategy:     mab.NewEpsilonGreedy(0),
+			Sampler:      mab.NewSha1Sampler(),
+			Holdout:      &mab.Holdout{Fraction: 0.1, Salt: "test", ControlArm: 0},
+		}
+
+		held := 0
+		for i := 0; i < 1000; i++ {
+			res, err := b.SelectArm(context.Background(), fmt.Sprintf("user-%d", i), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Holdout {
+				held++
+				assert.Equal(t, 0, res.Arm)
+				assert.Equal(t, 0.0, res.Propensity())
+			} else {
+				assert.Equal(t, 1, res.Arm)
+			}
+		}
+		assert.InDelta(t, 100, held, 30)
+		// held-out units also get the reward estimates, to check the control arm against them
+		assert.Equal(t, 1000, source.calls)
+	})
+
+	t.Run("control arm is not filtered", func(t *testing.T) {
+		b := mab.Bandit{
+			RewardSource: &mab.RewardStub{Rewards: rewards},
+			Strategy:     mab.NewEpsilonGreedy(0),
+			Sampler:      mab.NewSha1Sampler(),
+			Holdout:      &mab.Holdout{Fraction: 1, Salt: "test", ControlArm: 3},
+			Filters:      []mab.ArmFilter{mab.EligibilityMask("out of stock", true, true, true, false)},
+		}
+
+		res, err := b.SelectArm(context.Background(), "user-1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, res.Arm)
+		assert.Empty(t, res.Filtered)
+	})
+
+	t.Run("control arm out of range", func(t *testing.T) {
+		for _, arm := range []int{-1, 4} {
+			b := mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: rewards},
+				Strategy:     mab.NewEpsilonGreedy(0),
+				Sampler:      mab.NewSha1Sampler(),
+				Holdout:      &mab.Holdout{Fraction: 1, Salt: "test", ControlArm: arm},
+			}
+
+			res, err := b.SelectArm(context.Background(), "user-1", nil)
+			assert.True(t, errors.Is(err, mab.ErrInvalidParameter), "expected ErrInvalidParameter. got=%v", err)
+			assert.Equal(t, -1, res.Arm)
+		}
+	})
+
+	t.Run("uniform", func(t *testing.T) {
+		b := mab.Bandit{
+			RewardSource: &mab.RewardStub{Rewards: rewards},
+			Strategy:     mab.NewEpsilonGreedy(0),
+			Sampler:      mab.NewSha1Sampler(),
+			Holdout:      &mab.Holdout{Fraction: 0.5, Salt: "test", Uniform: true},
+		}
+
+		counts := make([]int, len(rewards))
+		held := 0
+		for i := 0; i < 3000; i++ {
+			res, err := b.SelectArm(context.Background(), fmt.Sprintf("user-%d", i), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Holdout {
+				assert.Equal(t, 1, res.Arm)
+				continue
+			}
+			held++
+			counts[res.Arm]++
+			assert.InDeltaSlice(t, []float64{1.0 / 3, 1.0 / 3, 0, 1.0 / 3}, res.Probs, 1e-9)
+		}
+
+		assert.Equal(t, 0, counts[2])
+		for _, arm := range []int{0, 1, 3} {
+			assert.InDelta(t, 1.0/3, float64(counts[arm])/float64(held), 0.05)
+		}
+	})
+}
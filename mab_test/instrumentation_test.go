@@ -0,0 +1,127 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey string
+
+type rewardSourceFunc func(ctx context.Context, banditContext interface{}) ([]mab.Dist, error)
+
+func (f rewardSourceFunc) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	return f(ctx, banditContext)
+}
+
+// recorder is a mab.Instrumentation that records the calls made to it, and checks that contexts are threaded through.
+type recorder struct {
+	calls   []string
+	stages  []mab.StageEvent
+	selects []mab.SelectEvent
+	ctxOK   bool
+}
+
+func (r *recorder) StartSelect(ctx context.Context, bandit string, unit string) context.Context {
+	r.calls = append(r.calls, "start select "+bandit)
+	return context.WithValue(ctx, ctxKey("select"), unit)
+}
+
+func (r *recorder) EndSelect(ctx context.Context, e mab.SelectEvent) {
+	r.calls = append(r.calls, "end select")
+	r.selects = append(r.selects, e)
+}
+
+func (r *recorder) StartStage(ctx context.Context, stage mab.Stage) context.Context {
+	r.calls = append(r.calls, "start "+string(stage))
+	return context.WithValue(ctx, ctxKey("stage"), stage)
+}
+
+func (r *recorder) EndStage(ctx context.Context, e mab.StageEvent) {
+	r.calls = append(r.calls, "end "+string(e.Stage))
+	r.ctxOK = ctx.Value(ctxKey("stage")) == e.Stage && ctx.Value(ctxKey("select")) != nil
+	r.stages = append(r.stages, e)
+}
+
+func TestBandit_Instrumentation(t *testing.T) {
+	rec := &recorder{}
+	var sourceCtx context.Context
+	bandit := &mab.Bandit{
+		Name: "test",
+		RewardSource: rewardSourceFunc(func(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+			sourceCtx = ctx
+			return []mab.Dist{mab.Point(0), mab.Point(1)}, nil
+		}),
+		Strategy:        mab.NewEpsilonGreedy(0),
+		Sampler:         mab.NewSha1Sampler(),
+		Filters:         []mab.ArmFilter{mab.EligibilityMask("ineligible", true, true)},
+		Instrumentation: rec,
+	}
+
+	res, err := bandit.SelectArm(context.Background(), "user1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []string{
+		"start select test",
+		"start rewards", "end rewards",
+		"start filter", "end filter",
+		"start probs", "end probs",
+		"start sample", "end sample",
+		"end select",
+	}, rec.calls)
+	assert.True(t, rec.ctxOK, "stage contexts not threaded through")
+	assert.Equal(t, mab.StageRewards, sourceCtx.Value(ctxKey("stage")), "reward source did not get the stage context")
+
+	assert.Len(t, rec.selects, 1)
+	assert.Equal(t, res, rec.selects[0].Result)
+	assert.Equal(t, "user1", rec.selects[0].Unit)
+	for _, e := range rec.stages {
+		assert.Equal(t, "test", e.Bandit)
+		assert.NoError(t, e.Err)
+	}
+}
+
+func TestBandit_Instrumentation_Error(t *testing.T) {
+	rec := &recorder{}
+	failure := errors.New("unavailable")
+	bandit := &mab.Bandit{
+		RewardSource: rewardSourceFunc(func(context.Context, interface{}) ([]mab.Dist, error) {
+			return nil, failure
+		}),
+		Strategy:        mab.NewEpsilonGreedy(0),
+		Sampler:         mab.NewSha1Sampler(),
+		Instrumentation: mab.MultiInstrumentation(rec),
+	}
+
+	_, err := bandit.SelectArm(context.Background(), "user1", nil)
+	assert.True(t, errors.Is(err, failure))
+
+	assert.Equal(t, []string{"start select ", "start rewards", "end rewards", "end select"}, rec.calls)
+	assert.Equal(t, failure, rec.stages[0].Err)
+	assert.Equal(t, err, rec.selects[0].Err)
+}
+
+func TestBandit_Instrumentation_Override(t *testing.T) {
+	rec := &recorder{}
+	overrides, err := mab.NewOverrideRules(mab.OverrideRule{Unit: "qa", Arm: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bandit := &mab.Bandit{
+		RewardSource:    &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0), mab.Point(1)}},
+		Strategy:        mab.NewEpsilonGreedy(0),
+		Sampler:         mab.NewSha1Sampler(),
+		Overrides:       overrides,
+		Instrumentation: rec,
+	}
+
+	if _, err := bandit.SelectArm(context.Background(), "qa", nil); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"start select ", "start override", "end override", "end select"}, rec.calls)
+}
@@ -0,0 +1,110 @@
+package mab
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+type observation struct {
+	banditContext interface{}
+	arm           int
+	reward        float64
+	propensity    float64
+}
+
+type learningStrategy struct {
+	mab.EpsilonGreedy
+	observed []observation
+	err      error
+}
+
+func (l *learningStrategy) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l.observed = append(l.observed, observation{banditContext, arm, reward, propensity})
+	return l.err
+}
+
+type learningSourceAndSampler struct {
+	mab.RewardStub
+	mab.Sha1Sampler
+	observed []observation
+}
+
+func (l *learningSourceAndSampler) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l.observed = append(l.observed, observation{banditContext, arm, reward, propensity})
+	return nil
+}
+
+func TestBandit_Observe(t *testing.T) {
+	source := mab.NewBayesianSource(2, mab.BetaPrior(1, 1))
+	strategy := &learningStrategy{}
+
+	b := mab.Bandit{
+		RewardSource: source,
+		Strategy:     strategy,
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	if err := b.Observe(context.Background(), "us", 1, 1, 0.25); err != nil {
+		t.Fatal(err)
+	}
+
+	rewards, err := source.GetRewards(context.Background(), "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRewards := []mab.Dist{mab.Beta(1, 1), mab.Beta(2, 1)}
+	if !assert.ObjectsAreEqualValues(expectedRewards, rewards) {
+		t.Errorf("rewards not %v. got=%v", expectedRewards, rewards)
+	}
+
+	expected := []observation{{"us", 1, 1, 0.25}}
+	if !assert.ObjectsAreEqualValues(expected, strategy.observed) {
+		t.Errorf("strategy observations not %v. got=%v", expected, strategy.observed)
+	}
+}
+
+func TestBandit_ObserveOnce(t *testing.T) {
+	component := &learningSourceAndSampler{}
+
+	b := mab.Bandit{
+		RewardSource: component,
+		Strategy:     mab.NewEpsilonGreedy(0.1),
+		Sampler:      component,
+	}
+
+	if err := b.Observe(context.Background(), nil, 0, 1, 0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(component.observed) != 1 {
+		t.Errorf("component should be observed once. got=%d", len(component.observed))
+	}
+}
+
+func TestBandit_ObserveError(t *testing.T) {
+	source := mab.NewBayesianSource(2, mab.BetaPrior(1, 1))
+	strategy := &learningStrategy{err: fmt.Errorf("strategy error")}
+
+	b := mab.Bandit{
+		RewardSource: source,
+		Strategy:     strategy,
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	// the source rejects the reward, but the strategy should still observe it
+	err := b.Observe(context.Background(), nil, 0, 2, 0.5)
+	if err == nil {
+		t.Fatal("expected error but didn't get one")
+	}
+	if err.Error() == "strategy error" {
+		t.Errorf("expected first error to be returned. got=%v", err)
+	}
+	if len(strategy.observed) != 1 {
+		t.Errorf("strategy should be observed once. got=%d", len(strategy.observed))
+	}
+}
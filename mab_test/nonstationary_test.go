@@ -0,0 +1,153 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestDiscountedPrior(t *testing.T) {
+	p := mab.DiscountedPrior(mab.BetaPrior(1, 1), 0.5)()
+
+	for _, r := range []float64{1, 1, 0} {
+		if err := p.Update(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// successes: (1*0.5 + 1)*0.5 + 0 = 0.75, failures: 1
+	expected := mab.Beta(1.75, 2)
+	if !assert.ObjectsAreEqualValues(expected, p.Dist()) {
+		t.Errorf("actual not %v. got=%v", expected, p.Dist())
+	}
+}
+
+func TestDiscountedPrior_Normal(t *testing.T) {
+	p := mab.DiscountedPrior(mab.NormalPrior(0, 1, 1), 1)()
+
+	for _, r := range []float64{1, 2, 3} {
+		if err := p.Update(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// with gamma = 1 the posterior is the same as the stationary posterior
+	expected := mab.Normal(1.5, 0.5)
+	actual := p.Dist().(mab.NormalDist)
+	if math.Abs(actual.Mu-expected.Mu) > 1e-9 || math.Abs(actual.Sigma-expected.Sigma) > 1e-9 {
+		t.Errorf("actual not %v. got=%v", expected, actual)
+	}
+}
+
+func TestCountWindowPrior(t *testing.T) {
+	p := mab.CountWindowPrior(mab.BetaPrior(1, 1), 2)()
+
+	tests := []struct {
+		reward   float64
+		expected mab.Dist
+	}{
+		{1, mab.Beta(2, 1)},
+		{1, mab.Beta(3, 1)},
+		{0, mab.Beta(2, 2)},
+		{0, mab.Beta(1, 3)},
+	}
+
+	for _, test := range tests {
+		if err := p.Update(test.reward); err != nil {
+			t.Fatal(err)
+		}
+		if !assert.ObjectsAreEqualValues(test.expected, p.Dist()) {
+			t.Errorf("actual not %v. got=%v", test.expected, p.Dist())
+		}
+	}
+}
+
+func TestCountWindowPrior_Recompute(t *testing.T) {
+	p := mab.CountWindowPrior(mab.NormalPrior(0, 1, 1), 2)()
+
+	// 1 is lost to rounding when added to 1e16, so the running sum is off by one after 1e16 leaves the window
+	for _, reward := range []float64{1e16, 1, 1, 1, 1} {
+		if err := p.Update(reward); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := mab.NormalPrior(0, 1, 1)()
+	for i := 0; i < 2; i++ {
+		if err := expected.Update(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if math.Abs(p.Dist().Mean()-expected.Dist().Mean()) > 1e-9 {
+		t.Errorf("actual not %v. got=%v", expected.Dist(), p.Dist())
+	}
+}
+
+func TestTimeWindowPrior(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+	source := mab.NewBayesianSource(2, mab.TimeWindowPrior(mab.BetaPrior(1, 1), time.Hour, clock))
+
+	check := func(expected []mab.Dist) {
+		t.Helper()
+		actual, err := source.GetRewards(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !assert.ObjectsAreEqualValues(expected, actual) {
+			t.Errorf("actual not %v. got=%v", expected, actual)
+		}
+	}
+
+	if err := source.Observe(context.Background(), nil, 0, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(30 * time.Minute)
+	if err := source.Observe(context.Background(), nil, 0, 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	check([]mab.Dist{mab.Beta(2, 2), mab.Beta(1, 1)})
+
+	clock.Advance(45 * time.Minute)
+	check([]mab.Dist{mab.Beta(1, 2), mab.Beta(1, 1)})
+
+	if err := source.Observe(context.Background(), nil, 1, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour + time.Second)
+	check([]mab.Dist{mab.Beta(1, 1), mab.Beta(1, 1)})
+}
+
+func TestNonStationaryPriorError(t *testing.T) {
+	tests := []struct {
+		name   string
+		prior  mab.Prior
+		reward float64
+	}{
+		{"invalid reward", mab.DiscountedPrior(mab.BetaPrior(1, 1), 0.9), 2},
+		{"zero gamma", mab.DiscountedPrior(mab.BetaPrior(1, 1), 0), 1},
+		{"gamma above one", mab.DiscountedPrior(mab.BetaPrior(1, 1), 1.1), 1},
+		{"window invalid reward", mab.CountWindowPrior(mab.NormalPrior(0, 1, 1), 10), math.NaN()},
+		{"non-conjugate prior", mab.CountWindowPrior(mab.DiscountedPrior(mab.BetaPrior(1, 1), 0.9), 10), 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.prior().Update(test.reward); !errors.Is(err, mab.ErrInvalidParameter) {
+				t.Errorf("expected ErrInvalidParameter. got=%v", err)
+			}
+		})
+	}
+}
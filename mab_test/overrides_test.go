@@ -0,0 +1,141 @@
+package mab
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverrideRules_Override(t *testing.T) {
+	overrides, err := mab.NewOverrideRules(
+		mab.OverrideRule{Name: "bug repro", Unit: "12345", Arm: 0},
+		mab.OverrideRule{Name: "qa", UnitPrefix: "test-", Arm: 2},
+		mab.OverrideRule{Name: "qa in us", UnitPrefix: "qa-", Context: func(c interface{}) bool { return c == "us" }, Arm: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		unit          string
+		banditContext interface{}
+		arm           int
+		forced        bool
+	}{
+		{"12345", nil, 0, true},
+		{"123456", nil, 0, false},
+		{"test-abc", nil, 2, true},
+		{"qa-abc", "us", 1, true},
+		{"qa-abc", "ca", 0, false},
+		{"user-1", "us", 0, false},
+	}
+
+	for _, test := range tests {
+		arm, ok := overrides.Override(context.Background(), test.unit, test.banditContext)
+		assert.Equal(t, test.forced, ok, test.unit)
+		if ok {
+			assert.Equal(t, test.arm, arm, test.unit)
+		}
+	}
+}
+
+func TestNewOverrideRules_Errors(t *testing.T) {
+	tests := map[string]mab.OverrideRule{
+		"no conditions": {Arm: 1},
+		"negative arm":  {Unit: "12345", Arm: -1},
+	}
+
+	for name, rule := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := mab.NewOverrideRules(rule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadOverrideRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overrides")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "overrides.json")
+	data := `{"rules": [{"name": "qa", "unit_prefix": "test-", "arm": 2}, {"unit": "12345", "arm": 0}]}`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := mab.LoadOverrideRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []mab.OverrideRule{{Name: "qa", UnitPrefix: "test-", Arm: 2}, {Unit: "12345", Arm: 0}}, overrides.Rules())
+
+	if err := ioutil.WriteFile(path, []byte(`{"rules": [{"arm": 2}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = mab.LoadOverrideRules(path)
+	assert.Error(t, err)
+
+	_, err = mab.LoadOverrideRules(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestBandit_SelectArm_Forced(t *testing.T) {
+	overrides, err := mab.NewOverrideRules(mab.OverrideRule{UnitPrefix: "test-", Arm: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []mab.Decision
+	source := &countingSource{RewardStub: mab.RewardStub{Rewards: []mab.Dist{mab.Point(0.9), mab.Point(0.1)}}}
+
+	b := mab.Bandit{
+		RewardSource: source,
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+		Overrides:    overrides,
+		Logger:       mab.DecisionLoggerFunc(func(ctx context.Context, d mab.Decision) { logged = append(logged, d) }),
+	}
+
+	res, err := b.SelectArm(context.Background(), "test-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, res.Forced)
+	assert.Equal(t, 1, res.Arm)
+	assert.Empty(t, res.Probs)
+	assert.Equal(t, 0, source.calls)
+	assert.Equal(t, 0.0, res.Propensity())
+
+	res, err = b.SelectArm(context.Background(), "user-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, res.Forced)
+	assert.Equal(t, 0, res.Arm)
+	assert.Equal(t, 1, source.calls)
+
+	if assert.Len(t, logged, 2) {
+		assert.True(t, logged[0].Result.Forced)
+		assert.Equal(t, 0.0, logged[0].Propensity())
+		assert.Equal(t, 1.0, logged[1].Propensity())
+	}
+}
+
+type countingSource struct {
+	mab.RewardStub
+	calls int
+}
+
+func (c *countingSource) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	c.calls++
+	return c.RewardStub.GetRewards(ctx, banditContext)
+}
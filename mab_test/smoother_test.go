@@ -0,0 +1,208 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+)
+
+func assertProbs(t *testing.T, expected, actual []float64) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("actual not %v. got=%v", expected, actual)
+	}
+	for i := range actual {
+		if math.Abs(actual[i]-expected[i]) > 1e-9 {
+			t.Errorf("actual not %v. got=%v", expected, actual)
+			return
+		}
+	}
+}
+
+func TestSmoother_ComputeProbs(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := mab.NewSmoother(mab.NewProportional(), 0.1, time.Minute, mab.WithSmoothingClock(clock))
+
+	compute := func(rewards ...mab.Dist) []float64 {
+		t.Helper()
+		probs, err := s.ComputeProbs(rewards)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return probs
+	}
+
+	// the first call is not smoothed
+	assertProbs(t, []float64{0.9, 0.1}, compute(mab.Point(9), mab.Point(1)))
+
+	// no time has passed, so the probabilities don't move
+	assertProbs(t, []float64{0.9, 0.1}, compute(mab.Point(2), mab.Point(8)))
+
+	// after one minute, the largest change is the max step
+	clock.Advance(time.Minute)
+	assertProbs(t, []float64{0.8, 0.2}, compute(mab.Point(2), mab.Point(8)))
+
+	// after half a minute, half the max step
+	clock.Advance(30 * time.Second)
+	assertProbs(t, []float64{0.75, 0.25}, compute(mab.Point(2), mab.Point(8)))
+
+	// after a long time, the target is reached
+	clock.Advance(time.Hour)
+	assertProbs(t, []float64{0.2, 0.8}, compute(mab.Point(2), mab.Point(8)))
+}
+
+func TestSmoother_Null(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := mab.NewSmoother(mab.NewProportional(), 0.1, time.Minute, mab.WithSmoothingClock(clock))
+
+	if _, err := s.ComputeProbs([]mab.Dist{mab.Point(5), mab.Point(3), mab.Point(2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the Null arm is zeroed immediately, and its share goes to the other arms in proportion to their last probabilities
+	probs, err := s.ComputeProbs([]mab.Dist{mab.Null(), mab.Point(3), mab.Point(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProbs(t, []float64{0, 0.6, 0.4}, probs)
+
+	// when every arm that had probability becomes Null, the target is used
+	probs, err = s.ComputeProbs([]mab.Dist{mab.Point(1), mab.Null(), mab.Null()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProbs(t, []float64{1, 0, 0}, probs)
+}
+
+func TestSmoother_BanditContext(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := mab.Bandit{
+		RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+			"us": {mab.Point(1), mab.Point(0)},
+			"uk": {mab.Point(0), mab.Point(1)},
+		}},
+		Strategy: mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(clock)),
+		Sampler:  mab.NewSha1Sampler(),
+	}
+
+	// each bandit context has its own last probabilities, so they are not smoothed towards each other
+	for _, banditContext := range []string{"us", "uk", "us"} {
+		res, err := b.SelectArm(context.Background(), "12345", banditContext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Propensity() != 1 {
+			t.Errorf("%s: expected unsmoothed probabilities. got=%v", banditContext, res.Probs)
+		}
+	}
+
+	stub := b.RewardSource.(*mab.ContextualRewardStub)
+	stub.Rewards["us"] = []mab.Dist{mab.Point(0), mab.Point(1)}
+	clock.Advance(time.Minute)
+
+	res, err := b.SelectArm(context.Background(), "12345", "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProbs(t, []float64{0.9, 0.1}, res.Probs)
+}
+
+func TestSmoother_PeekProbs(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(clock))
+
+	if _, err := s.ComputeContextualProbs(context.Background(), "us", []mab.Dist{mab.Point(1), mab.Point(0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// peeking a minute later smooths from the last emitted probabilities, but doesn't record the result
+	clock.Advance(time.Minute)
+	probs, err := s.PeekProbs(context.Background(), "us", []mab.Dist{mab.Point(0), mab.Point(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProbs(t, []float64{0.9, 0.1}, probs)
+
+	probs, err = s.ComputeContextualProbs(context.Background(), "us", []mab.Dist{mab.Point(1), mab.Point(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertProbs(t, []float64{1, 0}, probs)
+}
+
+func TestSmoother_Observe(t *testing.T) {
+	strategy := &learningStrategy{}
+	b := mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(1), mab.Point(0)}},
+		Strategy:     mab.NewSmoother(strategy, 0.1, time.Minute),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	if err := b.Observe(context.Background(), "us", 1, 0.5, 0.2); err != nil {
+		t.Fatal(err)
+	}
+	if len(strategy.observed) != 1 || strategy.observed[0] != (observation{"us", 1, 0.5, 0.2}) {
+		t.Errorf("expected the observation to reach the wrapped strategy. got=%v", strategy.observed)
+	}
+
+	if err := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute).Observe(context.Background(), nil, 0, 1, 1); err != nil {
+		t.Errorf("expected no error for a strategy that does not learn. got=%v", err)
+	}
+}
+
+func TestSmoother_ComputeProbsError(t *testing.T) {
+	rewards := []mab.Dist{mab.Point(1)}
+
+	for _, s := range []*mab.Smoother{
+		mab.NewSmoother(mab.NewEpsilonGreedy(0), 0, time.Minute),
+		mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, 0),
+		mab.NewSmoother(mab.NewEpsilonGreedy(2), 0.1, time.Minute),
+	} {
+		if _, err := s.ComputeProbs(rewards); err == nil {
+			t.Error("expected error but didn't get one")
+		}
+	}
+
+	s := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingKey(func(interface{}) (interface{}, error) {
+		return nil, errors.New("no key")
+	}))
+	if _, err := s.ComputeContextualProbs(context.Background(), "us", rewards); err == nil {
+		t.Error("expected error from the key function but didn't get one")
+	}
+}
+
+func TestSmoother_NonComparableContext(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(clock))
+	ctx := context.Background()
+	first, second := []mab.Dist{mab.Point(1), mab.Point(0)}, []mab.Dist{mab.Point(0), mab.Point(1)}
+
+	contexts := []struct{ a, b, other interface{} }{
+		{[]float64{1, 2}, []float64{1, 2}, []float64{2, 1}},
+		{map[string]interface{}{"x": 1.0, "y": "a"}, map[string]interface{}{"y": "a", "x": 1.0}, map[string]interface{}{"x": 2.0}},
+	}
+
+	for _, c := range contexts {
+		if _, err := s.ComputeContextualProbs(ctx, c.a, first); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Minute)
+
+		// an equal context smooths from the probabilities emitted for the first, a different one starts afresh
+		probs, err := s.ComputeContextualProbs(ctx, c.other, second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertProbs(t, []float64{0, 1}, probs)
+
+		probs, err = s.ComputeContextualProbs(ctx, c.b, second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertProbs(t, []float64{0.9, 0.1}, probs)
+	}
+}
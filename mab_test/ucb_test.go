@@ -0,0 +1,72 @@
+package mab
+
+import (
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUCB_ComputeProbs(t *testing.T) {
+	tests := []struct {
+		name     string
+		rewards  []mab.Dist
+		alpha    float64
+		expected []float64
+	}{
+		{
+			"empty",
+			[]mab.Dist{},
+			1,
+			[]float64{},
+		},
+		{
+			"null only",
+			[]mab.Dist{mab.Null(), mab.Null()},
+			1,
+			[]float64{0, 0},
+		},
+		{
+			"greedy",
+			[]mab.Dist{mab.Normal(1, 5), mab.Normal(2, 0.1)},
+			0,
+			[]float64{0, 1},
+		},
+		{
+			"optimistic",
+			[]mab.Dist{mab.Normal(1, 5), mab.Normal(2, 0.1)},
+			1,
+			[]float64{1, 0},
+		},
+		{
+			"ties",
+			[]mab.Dist{mab.Normal(1, 1), mab.Point(2), mab.Null()},
+			1,
+			[]float64{0.5, 0.5, 0},
+		},
+		{
+			"with nulls",
+			[]mab.Dist{mab.Null(), mab.Beta(10, 10), mab.Null()},
+			2,
+			[]float64{0, 1, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := mab.NewUCB(test.alpha).ComputeProbs(test.rewards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !assert.ObjectsAreEqualValues(test.expected, actual) {
+				t.Errorf("actual not %v. got=%v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUCB_ComputeProbsError(t *testing.T) {
+	if _, err := mab.NewUCB(-1).ComputeProbs([]mab.Dist{mab.Point(1)}); err == nil {
+		t.Error("expected error but didn't get one")
+	}
+}
@@ -0,0 +1,134 @@
+package mab
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+)
+
+func TestValidateDist(t *testing.T) {
+	tests := []struct {
+		dist  mab.Dist
+		valid bool
+	}{
+		{mab.Beta(1, 2), true},
+		{mab.Beta(0, 2), false},
+		{mab.Beta(1, math.Inf(1)), false},
+		{mab.Beta(math.NaN(), 1), false},
+		{mab.Normal(0.5, 0.1), true},
+		{mab.Normal(0.5, 0), false},
+		{mab.Normal(math.NaN(), 0.1), false},
+		{mab.Normal(math.Inf(-1), 0.1), false},
+		{mab.LogitNormal(-1, 0.5), true},
+		{mab.LogitNormal(-1, -0.5), false},
+		{mab.LogitNormal(-1, 0), false},
+		{mab.Point(0.3), true},
+		{mab.Null(), true},
+		{mab.Point(math.NaN()), false},
+		{mab.Point(math.Inf(1)), false},
+		{nil, false},
+	}
+
+	for _, test := range tests {
+		err := mab.ValidateDist(test.dist)
+		if test.valid && err != nil {
+			t.Errorf("%v: unexpected error: %v", test.dist, err)
+		}
+		if !test.valid && !errors.Is(err, mab.ErrInvalidDist) {
+			t.Errorf("%v: expected ErrInvalidDist. got=%v", test.dist, err)
+		}
+	}
+}
+
+func TestValidatingSource(t *testing.T) {
+	stub := &mab.RewardStub{Rewards: []mab.Dist{mab.Beta(2, 3), mab.Normal(math.NaN(), 1), mab.Null()}}
+
+	tests := []struct {
+		name     string
+		opts     []mab.ValidationOption
+		expected []mab.Dist
+	}{
+		{
+			"null invalid",
+			[]mab.ValidationOption{mab.WithInvalidDistPolicy(mab.NullInvalid())},
+			[]mab.Dist{mab.Beta(2, 3), mab.Null(), mab.Null()},
+		},
+		{
+			"substitute prior",
+			[]mab.ValidationOption{mab.WithInvalidDistPolicy(mab.SubstitutePrior(mab.BetaPrior(1, 1)))},
+			[]mab.Dist{mab.Beta(2, 3), mab.Beta(1, 1), mab.Null()},
+		},
+		{
+			"allowed types",
+			[]mab.ValidationOption{mab.WithAllowedDists(mab.Normal(0, 1)), mab.WithInvalidDistPolicy(mab.NullInvalid())},
+			[]mab.Dist{mab.Null(), mab.Null(), mab.Null()},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rewards, err := mab.NewValidatingSource(stub, test.opts...).GetRewards(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rewards) != len(test.expected) {
+				t.Fatalf("expected %d rewards. got=%v", len(test.expected), rewards)
+			}
+			for i := range rewards {
+				if rewards[i] != test.expected[i] {
+					t.Errorf("arm %d: expected %v. got=%v", i, test.expected[i], rewards[i])
+				}
+			}
+		})
+	}
+
+	if stub.Rewards[1] == mab.Null() {
+		t.Error("policy modified the wrapped source's rewards")
+	}
+}
+
+func TestValidatingSource_Error(t *testing.T) {
+	stub := &mab.RewardStub{Rewards: []mab.Dist{mab.Beta(2, 3), mab.Beta(-1, 1)}}
+
+	_, err := mab.NewValidatingSource(stub).GetRewards(context.Background(), nil)
+	if !errors.Is(err, mab.ErrInvalidDist) {
+		t.Errorf("expected ErrInvalidDist for invalid arm. got=%v", err)
+	}
+
+	_, err = mab.NewValidatingSource(stub, mab.WithNumArms(3), mab.WithInvalidDistPolicy(mab.NullInvalid())).
+		GetRewards(context.Background(), nil)
+	if !errors.Is(err, mab.ErrInvalidDist) {
+		t.Errorf("expected ErrInvalidDist for wrong number of arms. got=%v", err)
+	}
+
+	b := mab.Bandit{
+		RewardSource: mab.NewValidatingSource(stub),
+		Strategy:     mab.NewEpsilonGreedy(0.1),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+	_, err = b.SelectArm(context.Background(), "12345", nil)
+	var selectErr *mab.SelectError
+	if !errors.As(err, &selectErr) || selectErr.Stage != mab.StageRewards {
+		t.Errorf("expected invalid rewards to fail in the rewards stage. got=%v", err)
+	}
+}
+
+func TestValidatingSource_Observe(t *testing.T) {
+	source := mab.NewBayesianSource(2, mab.BetaPrior(1, 1))
+	b := mab.Bandit{RewardSource: mab.NewValidatingSource(source, mab.WithNumArms(2))}
+
+	if err := b.Observe(context.Background(), nil, 1, 1, 0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	rewards, err := b.GetRewards(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards[1] != mab.Beta(2, 1) {
+		t.Errorf("observation not passed to the wrapped source. got=%v", rewards[1])
+	}
+}
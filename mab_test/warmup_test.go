@@ -0,0 +1,137 @@
+package mab
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stitchfix/mab"
+)
+
+func TestWarmup_ComputeProbs(t *testing.T) {
+	tests := []struct {
+		name     string
+		rewards  []mab.Dist
+		minShare float64
+		expected []float64
+	}{
+		{
+			"empty",
+			[]mab.Dist{},
+			0.1,
+			[]float64{},
+		},
+		{
+			"warmed up",
+			[]mab.Dist{mab.Beta(60, 40), mab.Beta(150, 50)},
+			0.1,
+			[]float64{0, 1},
+		},
+		{
+			"new arm",
+			[]mab.Dist{mab.Beta(60, 40), mab.Beta(150, 50), mab.Beta(1, 1)},
+			0.1,
+			[]float64{0, 0.902, 0.098},
+		},
+		{
+			"half warmed up",
+			[]mab.Dist{mab.WithSampleSize(mab.Point(0.5), 50), mab.Point(0.7)},
+			0.2,
+			[]float64{0.1, 0.9},
+		},
+		{
+			"no sample size",
+			[]mab.Dist{mab.Point(0.5), mab.Point(0.7)},
+			0.2,
+			[]float64{0, 1},
+		},
+		{
+			"null arms",
+			[]mab.Dist{mab.Null(), mab.WithSampleSize(mab.Null(), 0), mab.Point(0.7)},
+			0.2,
+			[]float64{0, 0, 1},
+		},
+		{
+			"floors over one",
+			[]mab.Dist{mab.WithSampleSize(mab.Point(0.5), 0), mab.WithSampleSize(mab.Point(0.7), 0), mab.Point(0.9)},
+			0.8,
+			[]float64{0.5, 0.5, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := mab.NewWarmup(mab.NewEpsilonGreedy(0), test.minShare, 100).ComputeProbs(test.rewards)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(actual) != len(test.expected) {
+				t.Fatalf("actual not %v. got=%v", test.expected, actual)
+			}
+			for i := range actual {
+				if math.Abs(actual[i]-test.expected[i]) > 1e-9 {
+					t.Errorf("actual not %v. got=%v", test.expected, actual)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestWarmup_Blending(t *testing.T) {
+	w := mab.NewWarmup(mab.NewEpsilonGreedy(0), 0.2, 100)
+
+	// the new arm's share falls smoothly from the minimum share to its share under the wrapped strategy
+	prev := 1.0
+	for n := 0.0; n <= 120; n += 10 {
+		probs, err := w.ComputeProbs([]mab.Dist{mab.Point(0.7), mab.WithSampleSize(mab.Point(0.5), n)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if probs[1] > prev {
+			t.Errorf("share increased with more observations at n=%v: %v", n, probs)
+		}
+		if n == 0 && probs[1] != 0.2 {
+			t.Errorf("expected the minimum share for a new arm. got=%v", probs)
+		}
+		if n >= 100 && probs[1] != 0 {
+			t.Errorf("expected the wrapped strategy after warm-up. got=%v", probs)
+		}
+		prev = probs[1]
+	}
+}
+
+func TestWarmup_ComputeProbsError(t *testing.T) {
+	rewards := []mab.Dist{mab.Point(1)}
+
+	for _, w := range []*mab.Warmup{
+		mab.NewWarmup(mab.NewEpsilonGreedy(0), -0.1, 100),
+		mab.NewWarmup(mab.NewEpsilonGreedy(0), 1.1, 100),
+		mab.NewWarmup(mab.NewEpsilonGreedy(0), 0.1, 0),
+		mab.NewWarmup(mab.NewEpsilonGreedy(2), 0.1, 100),
+	} {
+		if _, err := w.ComputeProbs(rewards); err == nil {
+			t.Errorf("expected error for %+v but didn't get one", w)
+		}
+	}
+}
+
+func TestWarmup_Observe(t *testing.T) {
+	strategy := &learningStrategy{}
+	b := mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(1), mab.Point(0)}},
+		Strategy:     mab.NewWarmup(strategy, 0.1, 100),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	if err := b.Observe(context.Background(), "us", 1, 0.5, 0.2); err != nil {
+		t.Fatal(err)
+	}
+	if len(strategy.observed) != 1 || strategy.observed[0] != (observation{"us", 1, 0.5, 0.2}) {
+		t.Errorf("expected the observation to reach the wrapped strategy. got=%v", strategy.observed)
+	}
+
+	if err := mab.NewWarmup(mab.NewEpsilonGreedy(0), 0.1, 100).Observe(context.Background(), nil, 0, 1, 1); err != nil {
+		t.Errorf("expected no error for a strategy that does not learn. got=%v", err)
+	}
+}
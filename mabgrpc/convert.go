@@ -0,0 +1,196 @@
+package mabgrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabgrpc/mabpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// sampleSizeParam is the parameter that holds the sample size of a mab.SampledDist, which is sent as its underlying
+// Dist since the protobuf form has no nested distributions.
+const sampleSizeParam = "sample_size"
+
+// DistToProto converts a Dist to its protobuf form, using its tagged JSON encoding.
+// A mab.SampledDist is converted to the protobuf form of its underlying Dist, with an extra sample_size parameter.
+// Returns an error if the Dist cannot be encoded or has non-numeric parameters.
+func DistToProto(d mab.Dist) (*mabpb.Dist, error) {
+	if s, ok := d.(mab.SampledDist); ok {
+		pb, err := DistToProto(s.Dist)
+		if err != nil {
+			return nil, err
+		}
+		pb.Params[sampleSizeParam] = s.N
+		return pb, nil
+	}
+
+	data, err := mab.MarshalDist(d)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	pb := &mabpb.Dist{Params: make(map[string]float64, len(fields))}
+	for name, raw := range fields {
+		if name == "type" {
+			if err := json.Unmarshal(raw, &pb.Type); err != nil {
+				return nil, fmt.Errorf("invalid type: %w", err)
+			}
+			continue
+		}
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("%s parameter %s is not a number", pb.Type, name)
+		}
+		pb.Params[name] = v
+	}
+	return pb, nil
+}
+
+// DistFromProto converts the protobuf form of a Dist back to a Dist, using mab.DistFromJSON.
+// If there is a sample_size parameter, the Dist is wrapped with mab.WithSampleSize.
+func DistFromProto(pb *mabpb.Dist) (mab.Dist, error) {
+	fields := make(map[string]interface{}, len(pb.GetParams())+1)
+	for name, v := range pb.GetParams() {
+		fields[name] = v
+	}
+	fields["type"] = pb.GetType()
+
+	n, sampled := pb.GetParams()[sampleSizeParam]
+	delete(fields, sampleSizeParam)
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	d, err := mab.DistFromJSON(data)
+	if err != nil || !sampled {
+		return d, err
+	}
+	if n < 0 {
+		return nil, mab.Errorf(mab.ErrInvalidDist, "sample size must be >= 0. got=%f", n)
+	}
+	return mab.WithSampleSize(d, n), nil
+}
+
+// DistsToProto converts each Dist with DistToProto.
+func DistsToProto(dists []mab.Dist) ([]*mabpb.Dist, error) {
+	result := make([]*mabpb.Dist, len(dists))
+	for i, d := range dists {
+		pb, err := DistToProto(d)
+		if err != nil {
+			return nil, fmt.Errorf("arm %d: %w", i, err)
+		}
+		result[i] = pb
+	}
+	return result, nil
+}
+
+// DistsFromProto converts each Dist with DistFromProto.
+func DistsFromProto(pbs []*mabpb.Dist) ([]mab.Dist, error) {
+	result := make([]mab.Dist, len(pbs))
+	for i, pb := range pbs {
+		d, err := DistFromProto(pb)
+		if err != nil {
+			return nil, fmt.Errorf("arm %d: %w", i, err)
+		}
+		result[i] = d
+	}
+	return result, nil
+}
+
+// ResultToProto converts a Result to its protobuf form.
+func ResultToProto(r mab.Result) (*mabpb.Result, error) {
+	rewards, err := DistsToProto(r.Rewards)
+	if err != nil {
+		return nil, err
+	}
+	pb := &mabpb.Result{
+		DecisionId: r.DecisionID,
+		Rewards:    rewards,
+		Probs:      r.Probs,
+		Arm:        int32(r.Arm),
+		Forced:     r.Forced,
+		Holdout:    r.Holdout,
+	}
+	for _, f := range r.Filtered {
+		pb.Filtered = append(pb.Filtered, &mabpb.FilteredArm{Arm: int32(f.Arm), Reason: f.Reason})
+	}
+	return pb, nil
+}
+
+// ResultFromProto converts the protobuf form of a Result back to a Result.
+func ResultFromProto(pb *mabpb.Result) (mab.Result, error) {
+	rewards, err := DistsFromProto(pb.GetRewards())
+	if err != nil {
+		return mab.Result{}, err
+	}
+	r := mab.Result{
+		DecisionID: pb.GetDecisionId(),
+		Rewards:    rewards,
+		Probs:      pb.GetProbs(),
+		Arm:        int(pb.GetArm()),
+		Forced:     pb.GetForced(),
+		Holdout:    pb.GetHoldout(),
+	}
+	for _, f := range pb.GetFiltered() {
+		r.Filtered = append(r.Filtered, mab.FilteredArm{Arm: int(f.GetArm()), Reason: f.GetReason()})
+	}
+	return r, nil
+}
+
+// ContextFromProto converts a protobuf Value to a banditContext. Strings, numbers, booleans, lists and structs become
+// the same Go values as when decoding JSON into an interface{}, and a missing or null value is nil.
+func ContextFromProto(v *structpb.Value) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.AsInterface()
+}
+
+// A ContextDecoder converts the context of a request into the banditContext passed to the bandit.
+type ContextDecoder func(v *structpb.Value) (interface{}, error)
+
+// DecodeContext is the default ContextDecoder. It converts the context with ContextFromProto.
+func DecodeContext(v *structpb.Value) (interface{}, error) {
+	return ContextFromProto(v), nil
+}
+
+// DecodeFeatureVector is a ContextDecoder for contextual reward models such as linear.Model and logistic.Model, which
+// expect a []float64 feature vector. The context must be a list of numbers.
+func DecodeFeatureVector(v *structpb.Value) (interface{}, error) {
+	list := v.GetListValue()
+	if list == nil {
+		return nil, fmt.Errorf("feature vector must be a list of numbers")
+	}
+	x := make([]float64, len(list.GetValues()))
+	for i, f := range list.GetValues() {
+		n, ok := f.GetKind().(*structpb.Value_NumberValue)
+		if !ok {
+			return nil, fmt.Errorf("feature %d is not a number", i)
+		}
+		x[i] = n.NumberValue
+	}
+	return x, nil
+}
+
+// ContextToProto converts a banditContext to a protobuf Value via its JSON encoding.
+func ContextToProto(banditContext interface{}) (*structpb.Value, error) {
+	if banditContext == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(banditContext)
+	if err != nil {
+		return nil, err
+	}
+	v := &structpb.Value{}
+	if err := v.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
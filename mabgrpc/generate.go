@@ -0,0 +1,5 @@
+// Package mabgrpc serves bandits over gRPC, and provides a RewardSource that gets reward estimates from a gRPC reward
+// service. The service definitions are in proto/mab/v1/mab.proto, and the generated code is in the mabpb package.
+package mabgrpc
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/stitchfix/mab/mabgrpc --go-grpc_out=. --go-grpc_opt=module=github.com/stitchfix/mab/mabgrpc mab/v1/mab.proto
@@ -0,0 +1,21 @@
+module github.com/stitchfix/mab/mabgrpc
+
+go 1.21
+
+replace github.com/stitchfix/mab => ../
+
+require (
+	github.com/stitchfix/mab v0.2.0
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	golang.org/x/exp v0.0.0-20200224162631-6cc2880d07d6 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gonum.org/v1/gonum v0.8.2 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
@@ -0,0 +1,68 @@
+package mabgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabgrpc"
+	"github.com/stitchfix/mab/mabgrpc/mabpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRewardSource(t *testing.T) {
+	stub := &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+		"us": {mab.Beta(1, 2), mab.Normal(0.5, 0.1), mab.Point(0.3), mab.Null()},
+	}}
+	conn := dial(t, func(s *grpc.Server) {
+		mabpb.RegisterRewardServiceServer(s, mabgrpc.NewRewardServer(stub))
+	})
+	source := mabgrpc.NewRewardSource(conn)
+
+	rewards, err := source.GetRewards(context.Background(), "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rewards) != 4 {
+		t.Fatalf("expected 4 rewards. got=%v", rewards)
+	}
+	for i, want := range stub.Rewards["us"] {
+		if rewards[i] != want {
+			t.Errorf("arm %d not round-tripped. got=%v want=%v", i, rewards[i], want)
+		}
+	}
+
+	_, err = source.GetRewards(context.Background(), "ca")
+	if status.Code(err) != codes.InvalidArgument || !errors.Is(err, mab.ErrInvalidContext) {
+		t.Errorf("expected InvalidArgument for unknown context. got=%v", err)
+	}
+}
+
+func TestDistToProto(t *testing.T) {
+	pb, err := mabgrpc.DistToProto(mab.Beta(2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.GetType() != "beta" || pb.GetParams()["alpha"] != 2 || pb.GetParams()["beta"] != 3 {
+		t.Errorf("unexpected proto: %v", pb)
+	}
+
+	sampled := mab.WithSampleSize(mab.Beta(2, 3), 3)
+	pb, err = mabgrpc.DistToProto(sampled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.GetType() != "beta" || pb.GetParams()["sample_size"] != 3 {
+		t.Errorf("unexpected proto: %v", pb)
+	}
+	if d, err := mabgrpc.DistFromProto(pb); err != nil || d != mab.Dist(sampled) {
+		t.Errorf("expected %v. got=%v, %v", sampled, d, err)
+	}
+
+	if _, err := mabgrpc.DistFromProto(&mabpb.Dist{Type: "unknown"}); err == nil {
+		t.Error("expected error for unknown type but didn't get one")
+	}
+}
@@ -0,0 +1,326 @@
+package mabgrpc_test
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/linear"
+	"github.com/stitchfix/mab/mabgrpc"
+	"github.com/stitchfix/mab/mabgrpc/mabpb"
+	"github.com/stitchfix/mab/registry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func newRegistry() *registry.Registry {
+	reg := registry.New()
+	reg.Set("stub", "v1", &mab.Bandit{
+		RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+			"us": {mab.Beta(10, 20), mab.Beta(20, 10)},
+		}},
+		Strategy: mab.NewEpsilonGreedy(0.1),
+		Sampler:  mab.NewSha1Sampler(),
+	})
+	reg.Set("learning", "v2", &mab.Bandit{
+		RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	})
+	failing := doerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 503, Body: ioutil.NopCloser(strings.NewReader("overloaded"))}, nil
+	})
+	reg.Set("failing", "v3", &mab.Bandit{
+		RewardSource: mab.NewHTTPSource(failing, "http://rewards", mab.ParseFunc(mab.BetaFromJSON)),
+		Strategy:     mab.NewEpsilonGreedy(0.1),
+		Sampler:      mab.NewSha1Sampler(),
+	})
+	return reg
+}
+
+// dial starts a gRPC server on an in-memory listener, registers services on it, and returns a connection to it.
+func dial(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func banditClient(t *testing.T, bandits mabgrpc.Bandits) mabpb.BanditServiceClient {
+	conn := dial(t, func(s *grpc.Server) {
+		mabpb.RegisterBanditServiceServer(s, mabgrpc.NewServer(bandits, mabgrpc.WithMaxBatchSize(3)))
+	})
+	return mabpb.NewBanditServiceClient(conn)
+}
+
+func TestServer_SelectArm(t *testing.T) {
+	client := banditClient(t, newRegistry())
+	ctx := context.Background()
+
+	resp, err := client.SelectArm(ctx, &mabpb.SelectArmRequest{Bandit: "stub", Unit: "user1", Context: structpb.NewStringValue("us")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetBandit() != "stub" || resp.GetVersion() != "v1" {
+		t.Errorf("unexpected bandit and version: %v", resp)
+	}
+
+	result, err := mabgrpc.ResultFromProto(resp.GetResult())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DecisionID == "" || len(result.Probs) != 2 || len(result.Rewards) != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Rewards[1] != mab.Beta(20, 10) {
+		t.Errorf("reward not round-tripped. got=%v", result.Rewards[1])
+	}
+
+	_, err = client.SelectArm(ctx, &mabpb.SelectArmRequest{Bandit: "missing", Unit: "user1"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound. got=%v", err)
+	}
+
+	_, err = client.SelectArm(ctx, &mabpb.SelectArmRequest{Unit: "user1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for ambiguous bandit. got=%v", err)
+	}
+
+	_, err = client.SelectArm(ctx, &mabpb.SelectArmRequest{Bandit: "failing", Unit: "user1"})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable for reward service error. got=%v", err)
+	}
+}
+
+func TestServer_SingleBandit(t *testing.T) {
+	bandit := &mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0), mab.Point(1)}},
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+	client := banditClient(t, mabgrpc.Single("only", bandit))
+
+	resp, err := client.SelectArm(context.Background(), &mabpb.SelectArmRequest{Unit: "user1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetBandit() != "only" || resp.GetResult().GetArm() != 1 {
+		t.Errorf("unexpected response: %v", resp)
+	}
+}
+
+func TestServer_GetProbabilitiesContextual(t *testing.T) {
+	frozen := mab.ClockFunc(func() time.Time { return time.Unix(0, 0) })
+	client := banditClient(t, mabgrpc.Single("smoothed", &mab.Bandit{
+		RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+			"us": {mab.Point(1), mab.Point(0)},
+			"uk": {mab.Point(0), mab.Point(1)},
+		}},
+		Strategy: mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(frozen)),
+		Sampler:  mab.NewSha1Sampler(),
+	}))
+
+	// the Smoother keeps separate probabilities for each context, so the second context is not smoothed towards the first
+	for _, test := range []struct {
+		context  string
+		expected []float64
+	}{
+		{"us", []float64{1, 0}},
+		{"uk", []float64{0, 1}},
+	} {
+		resp, err := client.GetProbabilities(context.Background(), &mabpb.GetProbabilitiesRequest{Context: structpb.NewStringValue(test.context)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if probs := resp.GetProbs(); len(probs) != 2 || probs[0] != test.expected[0] || probs[1] != test.expected[1] {
+			t.Errorf("%s: expected %v. got=%v", test.context, test.expected, probs)
+		}
+	}
+}
+
+func TestServer_GetProbabilitiesDoesNotUpdateSmoother(t *testing.T) {
+	now := time.Unix(0, 0)
+	stub := &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{"us": {mab.Point(1), mab.Point(0)}}}
+	client := banditClient(t, mabgrpc.Single("smoothed", &mab.Bandit{
+		RewardSource: stub,
+		Strategy:     mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(mab.ClockFunc(func() time.Time { return now }))),
+		Sampler:      mab.NewSha1Sampler(),
+	}))
+	ctx := context.Background()
+	us := structpb.NewStringValue("us")
+
+	if _, err := client.SelectArm(ctx, &mabpb.SelectArmRequest{Unit: "user1", Context: us}); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute)
+	stub.Rewards["us"] = []mab.Dist{mab.Point(0), mab.Point(1)}
+	probs, err := client.GetProbabilities(ctx, &mabpb.GetProbabilitiesRequest{Context: us})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := probs.GetProbs(); len(p) != 2 || math.Abs(p[0]-0.9) > 1e-9 {
+		t.Errorf("expected smoothed probabilities. got=%v", p)
+	}
+
+	// live traffic still smooths from the probabilities it was last given
+	stub.Rewards["us"] = []mab.Dist{mab.Point(1), mab.Point(0)}
+	resp, err := client.SelectArm(ctx, &mabpb.SelectArmRequest{Unit: "user1", Context: us})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := resp.GetResult().GetProbs(); len(p) != 2 || p[0] != 1 {
+		t.Errorf("expected probabilities unchanged by GetProbabilities. got=%v", p)
+	}
+}
+
+func TestServer_ContextDecoder(t *testing.T) {
+	model := linear.NewModel(2, 2)
+	conn := dial(t, func(s *grpc.Server) {
+		bandit := &mab.Bandit{RewardSource: model, Strategy: mab.NewEpsilonGreedy(0), Sampler: mab.NewSha1Sampler()}
+		mabpb.RegisterBanditServiceServer(s, mabgrpc.NewServer(mabgrpc.Single("linear", bandit), mabgrpc.WithContextDecoder(mabgrpc.DecodeFeatureVector)))
+	})
+	client := mabpb.NewBanditServiceClient(conn)
+	ctx := context.Background()
+
+	features, err := structpb.NewValue([]interface{}{1, 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Observe(ctx, &mabpb.ObserveRequest{Context: features, Arm: 1, Reward: 1, Propensity: 0.5}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := client.SelectArm(ctx, &mabpb.SelectArmRequest{Unit: "user1", Context: features})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetResult().GetArm() != 1 {
+		t.Errorf("expected arm 1 after observations. got=%v", resp.GetResult())
+	}
+
+	_, err = client.GetProbabilities(ctx, &mabpb.GetProbabilitiesRequest{Context: structpb.NewStringValue("us")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a context that is not a feature vector. got=%v", err)
+	}
+}
+
+func TestServer_SelectArms(t *testing.T) {
+	client := banditClient(t, newRegistry())
+	ctx := context.Background()
+
+	resp, err := client.SelectArms(ctx, &mabpb.SelectArmsRequest{
+		Bandit: "stub",
+		Requests: []*mabpb.SelectArmRequest{
+			{Unit: "user1", Context: structpb.NewStringValue("us")},
+			{Unit: "user2", Context: structpb.NewStringValue("unknown")},
+			{Bandit: "missing", Unit: "user3"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := resp.GetResults()
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results. got=%d", len(results))
+	}
+	if results[0].GetError() != nil || results[0].GetResult() == nil {
+		t.Errorf("expected success for first request. got=%v", results[0])
+	}
+	if results[1].GetError().GetCode() != int32(codes.InvalidArgument) {
+		t.Errorf("expected InvalidArgument for unknown context. got=%v", results[1])
+	}
+	if results[2].GetError().GetCode() != int32(codes.NotFound) {
+		t.Errorf("expected NotFound for unknown bandit. got=%v", results[2])
+	}
+
+	_, err = client.SelectArms(ctx, &mabpb.SelectArmsRequest{Requests: make([]*mabpb.SelectArmRequest, 4)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for batch too large. got=%v", err)
+	}
+}
+
+func TestServer_SelectArmsDoesNotModifyRequest(t *testing.T) {
+	req := &mabpb.SelectArmsRequest{
+		Bandit:   "stub",
+		Requests: []*mabpb.SelectArmRequest{{Unit: "user1", Context: structpb.NewStringValue("us")}},
+	}
+
+	resp, err := mabgrpc.NewServer(newRegistry()).SelectArms(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetResults()[0].GetBandit() != "stub" {
+		t.Errorf("expected the batch bandit to be used. got=%v", resp.GetResults()[0])
+	}
+	if req.GetRequests()[0].GetBandit() != "" {
+		t.Errorf("expected the request to be unchanged. got=%v", req.GetRequests()[0])
+	}
+}
+
+func TestServer_ObserveAndGetProbabilities(t *testing.T) {
+	client := banditClient(t, newRegistry())
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		_, err := client.Observe(ctx, &mabpb.ObserveRequest{Bandit: "learning", Arm: 1, Reward: 1, Propensity: 0.5})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := client.GetProbabilities(ctx, &mabpb.GetProbabilitiesRequest{Bandit: "learning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetVersion() != "v2" {
+		t.Errorf("unexpected version: %v", resp)
+	}
+	if probs := resp.GetProbs(); len(probs) != 2 || probs[1] != 1 {
+		t.Errorf("expected arm 1 to have probability 1 after observations. got=%v", probs)
+	}
+
+	rewards, err := mabgrpc.DistsFromProto(resp.GetRewards())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards[1] != mab.Beta(11, 1) {
+		t.Errorf("unexpected reward estimate for arm 1. got=%v", rewards[1])
+	}
+
+	_, err = client.Observe(ctx, &mabpb.ObserveRequest{Bandit: "learning", Arm: 5, Reward: 1, Propensity: 0.5})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal for invalid arm. got=%v", err)
+	}
+}
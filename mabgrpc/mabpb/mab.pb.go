@@ -0,0 +1,1247 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: mab/v1/mab.proto
+
+package mabpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Dist is a reward distribution, such as {type: "beta", params: {alpha: 2, beta: 3}}.
+// The type and parameter names are the same as in the tagged JSON encoding of a mab.Dist.
+// A distribution with a known sample size has an extra "sample_size" parameter.
+type Dist struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   string             `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Params map[string]float64 `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+}
+
+func (x *Dist) Reset() {
+	*x = Dist{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Dist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dist) ProtoMessage() {}
+
+func (x *Dist) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dist.ProtoReflect.Descriptor instead.
+func (*Dist) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Dist) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Dist) GetParams() map[string]float64 {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type FilteredArm struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Arm    int32  `protobuf:"varint,1,opt,name=arm,proto3" json:"arm,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *FilteredArm) Reset() {
+	*x = FilteredArm{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilteredArm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilteredArm) ProtoMessage() {}
+
+func (x *FilteredArm) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilteredArm.ProtoReflect.Descriptor instead.
+func (*FilteredArm) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FilteredArm) GetArm() int32 {
+	if x != nil {
+		return x.Arm
+	}
+	return 0
+}
+
+func (x *FilteredArm) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DecisionId string         `protobuf:"bytes,1,opt,name=decision_id,json=decisionId,proto3" json:"decision_id,omitempty"`
+	Rewards    []*Dist        `protobuf:"bytes,2,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	Probs      []float64      `protobuf:"fixed64,3,rep,packed,name=probs,proto3" json:"probs,omitempty"`
+	Arm        int32          `protobuf:"varint,4,opt,name=arm,proto3" json:"arm,omitempty"`
+	Filtered   []*FilteredArm `protobuf:"bytes,5,rep,name=filtered,proto3" json:"filtered,omitempty"`
+	Forced     bool           `protobuf:"varint,6,opt,name=forced,proto3" json:"forced,omitempty"`
+	Holdout    bool           `protobuf:"varint,7,opt,name=holdout,proto3" json:"holdout,omitempty"`
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Result) GetDecisionId() string {
+	if x != nil {
+		return x.DecisionId
+	}
+	return ""
+}
+
+func (x *Result) GetRewards() []*Dist {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+func (x *Result) GetProbs() []float64 {
+	if x != nil {
+		return x.Probs
+	}
+	return nil
+}
+
+func (x *Result) GetArm() int32 {
+	if x != nil {
+		return x.Arm
+	}
+	return 0
+}
+
+func (x *Result) GetFiltered() []*FilteredArm {
+	if x != nil {
+		return x.Filtered
+	}
+	return nil
+}
+
+func (x *Result) GetForced() bool {
+	if x != nil {
+		return x.Forced
+	}
+	return false
+}
+
+func (x *Result) GetHoldout() bool {
+	if x != nil {
+		return x.Holdout
+	}
+	return false
+}
+
+// Error describes a failed request in a batch. Code is a gRPC status code.
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Error) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SelectArmRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// bandit can be omitted if only one bandit is served.
+	Bandit  string          `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Unit    string          `protobuf:"bytes,2,opt,name=unit,proto3" json:"unit,omitempty"`
+	Context *structpb.Value `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+}
+
+func (x *SelectArmRequest) Reset() {
+	*x = SelectArmRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SelectArmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectArmRequest) ProtoMessage() {}
+
+func (x *SelectArmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectArmRequest.ProtoReflect.Descriptor instead.
+func (*SelectArmRequest) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SelectArmRequest) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *SelectArmRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *SelectArmRequest) GetContext() *structpb.Value {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type SelectArmResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bandit  string  `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Version string  `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Result  *Result `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	// error is only set for failed requests in a batch.
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SelectArmResponse) Reset() {
+	*x = SelectArmResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SelectArmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectArmResponse) ProtoMessage() {}
+
+func (x *SelectArmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectArmResponse.ProtoReflect.Descriptor instead.
+func (*SelectArmResponse) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SelectArmResponse) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *SelectArmResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SelectArmResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *SelectArmResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type SelectArmsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// bandit is used for each request that does not name its own bandit.
+	Bandit   string              `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Requests []*SelectArmRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *SelectArmsRequest) Reset() {
+	*x = SelectArmsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SelectArmsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectArmsRequest) ProtoMessage() {}
+
+func (x *SelectArmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectArmsRequest.ProtoReflect.Descriptor instead.
+func (*SelectArmsRequest) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SelectArmsRequest) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *SelectArmsRequest) GetRequests() []*SelectArmRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type SelectArmsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*SelectArmResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *SelectArmsResponse) Reset() {
+	*x = SelectArmsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SelectArmsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectArmsResponse) ProtoMessage() {}
+
+func (x *SelectArmsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectArmsResponse.ProtoReflect.Descriptor instead.
+func (*SelectArmsResponse) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SelectArmsResponse) GetResults() []*SelectArmResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ObserveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bandit     string          `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Context    *structpb.Value `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	Arm        int32           `protobuf:"varint,3,opt,name=arm,proto3" json:"arm,omitempty"`
+	Reward     float64         `protobuf:"fixed64,4,opt,name=reward,proto3" json:"reward,omitempty"`
+	Propensity float64         `protobuf:"fixed64,5,opt,name=propensity,proto3" json:"propensity,omitempty"`
+}
+
+func (x *ObserveRequest) Reset() {
+	*x = ObserveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObserveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObserveRequest) ProtoMessage() {}
+
+func (x *ObserveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObserveRequest.ProtoReflect.Descriptor instead.
+func (*ObserveRequest) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ObserveRequest) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *ObserveRequest) GetContext() *structpb.Value {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *ObserveRequest) GetArm() int32 {
+	if x != nil {
+		return x.Arm
+	}
+	return 0
+}
+
+func (x *ObserveRequest) GetReward() float64 {
+	if x != nil {
+		return x.Reward
+	}
+	return 0
+}
+
+func (x *ObserveRequest) GetPropensity() float64 {
+	if x != nil {
+		return x.Propensity
+	}
+	return 0
+}
+
+type ObserveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ObserveResponse) Reset() {
+	*x = ObserveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObserveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObserveResponse) ProtoMessage() {}
+
+func (x *ObserveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObserveResponse.ProtoReflect.Descriptor instead.
+func (*ObserveResponse) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{9}
+}
+
+type GetProbabilitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bandit  string          `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Context *structpb.Value `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+}
+
+func (x *GetProbabilitiesRequest) Reset() {
+	*x = GetProbabilitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProbabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProbabilitiesRequest) ProtoMessage() {}
+
+func (x *GetProbabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProbabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetProbabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetProbabilitiesRequest) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *GetProbabilitiesRequest) GetContext() *structpb.Value {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type GetProbabilitiesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bandit  string    `protobuf:"bytes,1,opt,name=bandit,proto3" json:"bandit,omitempty"`
+	Version string    `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Rewards []*Dist   `protobuf:"bytes,3,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	Probs   []float64 `protobuf:"fixed64,4,rep,packed,name=probs,proto3" json:"probs,omitempty"`
+}
+
+func (x *GetProbabilitiesResponse) Reset() {
+	*x = GetProbabilitiesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetProbabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProbabilitiesResponse) ProtoMessage() {}
+
+func (x *GetProbabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProbabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetProbabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetProbabilitiesResponse) GetBandit() string {
+	if x != nil {
+		return x.Bandit
+	}
+	return ""
+}
+
+func (x *GetProbabilitiesResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetProbabilitiesResponse) GetRewards() []*Dist {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+func (x *GetProbabilitiesResponse) GetProbs() []float64 {
+	if x != nil {
+		return x.Probs
+	}
+	return nil
+}
+
+type GetRewardsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Context *structpb.Value `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+}
+
+func (x *GetRewardsRequest) Reset() {
+	*x = GetRewardsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRewardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRewardsRequest) ProtoMessage() {}
+
+func (x *GetRewardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRewardsRequest.ProtoReflect.Descriptor instead.
+func (*GetRewardsRequest) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRewardsRequest) GetContext() *structpb.Value {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type GetRewardsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rewards []*Dist `protobuf:"bytes,1,rep,name=rewards,proto3" json:"rewards,omitempty"`
+}
+
+func (x *GetRewardsResponse) Reset() {
+	*x = GetRewardsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mab_v1_mab_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRewardsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRewardsResponse) ProtoMessage() {}
+
+func (x *GetRewardsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mab_v1_mab_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRewardsResponse.ProtoReflect.Descriptor instead.
+func (*GetRewardsResponse) Descriptor() ([]byte, []int) {
+	return file_mab_v1_mab_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetRewardsResponse) GetRewards() []*Dist {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+var File_mab_v1_mab_proto protoreflect.FileDescriptor
+
+var file_mab_v1_mab_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x6d, 0x61, 0x62, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x62, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x06, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75,
+	0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x87, 0x01, 0x0a, 0x04, 0x44, 0x69, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x69, 0x73, 0x74, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0x37, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x41, 0x72,
+	0x6d, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03,
+	0x61, 0x72, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xdc, 0x01, 0x0a, 0x06,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x63, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x63,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x62, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x01, 0x52, 0x05,
+	0x70, 0x72, 0x6f, 0x62, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x72, 0x6d, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x61, 0x72, 0x6d, 0x12, 0x2f, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x65, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x41, 0x72, 0x6d, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x63,
+	0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x68, 0x6f, 0x6c, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x68, 0x6f, 0x6c, 0x64, 0x6f, 0x75, 0x74, 0x22, 0x35, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x70, 0x0a, 0x10, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x22, 0x92, 0x01, 0x0a, 0x11, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72,
+	0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x6e,
+	0x64, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6d, 0x61,
+	0x62, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x23, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x61, 0x0a, 0x11, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x41, 0x72, 0x6d, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x62, 0x61, 0x6e, 0x64, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
+	0x61, 0x6e, 0x64, 0x69, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x49, 0x0a, 0x12, 0x53,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x41, 0x72, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0xa4, 0x01, 0x0a, 0x0e, 0x4f, 0x62, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x6e,
+	0x64, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69,
+	0x74, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x72, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x61, 0x72, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x12, 0x1e, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x22, 0x11, 0x0a,
+	0x0f, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x63, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x62,
+	0x61, 0x6e, 0x64, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e,
+	0x64, 0x69, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0x8a, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e, 0x64, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x69, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x62, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x62, 0x73, 0x22, 0x45, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0x3c, 0x0a, 0x12, 0x47, 0x65, 0x74,
+	0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x26, 0x0a, 0x07, 0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x73, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x32, 0xa9, 0x02, 0x0a, 0x0d, 0x42, 0x61, 0x6e, 0x64,
+	0x69, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x12, 0x18, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x41, 0x72, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x53,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x62, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x41, 0x72, 0x6d, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3a, 0x0a, 0x07, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x12, 0x16, 0x2e, 0x6d, 0x61,
+	0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x62, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x10,
+	0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x1f, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f,
+	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x20, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x32, 0x54, 0x0a, 0x0d, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x77, 0x61, 0x72,
+	0x64, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52,
+	0x65, 0x77, 0x61, 0x72, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
+	0x6d, 0x61, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x77, 0x61, 0x72, 0x64,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x74, 0x69, 0x74, 0x63, 0x68, 0x66, 0x69,
+	0x78, 0x2f, 0x6d, 0x61, 0x62, 0x2f, 0x6d, 0x61, 0x62, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x6d, 0x61,
+	0x62, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_mab_v1_mab_proto_rawDescOnce sync.Once
+	file_mab_v1_mab_proto_rawDescData = file_mab_v1_mab_proto_rawDesc
+)
+
+func file_mab_v1_mab_proto_rawDescGZIP() []byte {
+	file_mab_v1_mab_proto_rawDescOnce.Do(func() {
+		file_mab_v1_mab_proto_rawDescData = protoimpl.X.CompressGZIP(file_mab_v1_mab_proto_rawDescData)
+	})
+	return file_mab_v1_mab_proto_rawDescData
+}
+
+var file_mab_v1_mab_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_mab_v1_mab_proto_goTypes = []any{
+	(*Dist)(nil),                     // 0: mab.v1.Dist
+	(*FilteredArm)(nil),              // 1: mab.v1.FilteredArm
+	(*Result)(nil),                   // 2: mab.v1.Result
+	(*Error)(nil),                    // 3: mab.v1.Error
+	(*SelectArmRequest)(nil),         // 4: mab.v1.SelectArmRequest
+	(*SelectArmResponse)(nil),        // 5: mab.v1.SelectArmResponse
+	(*SelectArmsRequest)(nil),        // 6: mab.v1.SelectArmsRequest
+	(*SelectArmsResponse)(nil),       // 7: mab.v1.SelectArmsResponse
+	(*ObserveRequest)(nil),           // 8: mab.v1.ObserveRequest
+	(*ObserveResponse)(nil),          // 9: mab.v1.ObserveResponse
+	(*GetProbabilitiesRequest)(nil),  // 10: mab.v1.GetProbabilitiesRequest
+	(*GetProbabilitiesResponse)(nil), // 11: mab.v1.GetProbabilitiesResponse
+	(*GetRewardsRequest)(nil),        // 12: mab.v1.GetRewardsRequest
+	(*GetRewardsResponse)(nil),       // 13: mab.v1.GetRewardsResponse
+	nil,                              // 14: mab.v1.Dist.ParamsEntry
+	(*structpb.Value)(nil),           // 15: google.protobuf.Value
+}
+var file_mab_v1_mab_proto_depIdxs = []int32{
+	14, // 0: mab.v1.Dist.params:type_name -> mab.v1.Dist.ParamsEntry
+	0,  // 1: mab.v1.Result.rewards:type_name -> mab.v1.Dist
+	1,  // 2: mab.v1.Result.filtered:type_name -> mab.v1.FilteredArm
+	15, // 3: mab.v1.SelectArmRequest.context:type_name -> google.protobuf.Value
+	2,  // 4: mab.v1.SelectArmResponse.result:type_name -> mab.v1.Result
+	3,  // 5: mab.v1.SelectArmResponse.error:type_name -> mab.v1.Error
+	4,  // 6: mab.v1.SelectArmsRequest.requests:type_name -> mab.v1.SelectArmRequest
+	5,  // 7: mab.v1.SelectArmsResponse.results:type_name -> mab.v1.SelectArmResponse
+	15, // 8: mab.v1.ObserveRequest.context:type_name -> google.protobuf.Value
+	15, // 9: mab.v1.GetProbabilitiesRequest.context:type_name -> google.protobuf.Value
+	0,  // 10: mab.v1.GetProbabilitiesResponse.rewards:type_name -> mab.v1.Dist
+	15, // 11: mab.v1.GetRewardsRequest.context:type_name -> google.protobuf.Value
+	0,  // 12: mab.v1.GetRewardsResponse.rewards:type_name -> mab.v1.Dist
+	4,  // 13: mab.v1.BanditService.SelectArm:input_type -> mab.v1.SelectArmRequest
+	6,  // 14: mab.v1.BanditService.SelectArms:input_type -> mab.v1.SelectArmsRequest
+	8,  // 15: mab.v1.BanditService.Observe:input_type -> mab.v1.ObserveRequest
+	10, // 16: mab.v1.BanditService.GetProbabilities:input_type -> mab.v1.GetProbabilitiesRequest
+	12, // 17: mab.v1.RewardService.GetRewards:input_type -> mab.v1.GetRewardsRequest
+	5,  // 18: mab.v1.BanditService.SelectArm:output_type -> mab.v1.SelectArmResponse
+	7,  // 19: mab.v1.BanditService.SelectArms:output_type -> mab.v1.SelectArmsResponse
+	9,  // 20: mab.v1.BanditService.Observe:output_type -> mab.v1.ObserveResponse
+	11, // 21: mab.v1.BanditService.GetProbabilities:output_type -> mab.v1.GetProbabilitiesResponse
+	13, // 22: mab.v1.RewardService.GetRewards:output_type -> mab.v1.GetRewardsResponse
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_mab_v1_mab_proto_init() }
+func file_mab_v1_mab_proto_init() {
+	if File_mab_v1_mab_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_mab_v1_mab_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Dist); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*FilteredArm); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Error); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*SelectArmRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SelectArmResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*SelectArmsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*SelectArmsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ObserveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*ObserveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*GetProbabilitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*GetProbabilitiesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRewardsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mab_v1_mab_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRewardsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_mab_v1_mab_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_mab_v1_mab_proto_goTypes,
+		DependencyIndexes: file_mab_v1_mab_proto_depIdxs,
+		MessageInfos:      file_mab_v1_mab_proto_msgTypes,
+	}.Build()
+	File_mab_v1_mab_proto = out.File
+	file_mab_v1_mab_proto_rawDesc = nil
+	file_mab_v1_mab_proto_goTypes = nil
+	file_mab_v1_mab_proto_depIdxs = nil
+}
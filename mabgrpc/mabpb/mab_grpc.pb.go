@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: mab/v1/mab.proto
+
+package mabpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BanditService_SelectArm_FullMethodName        = "/mab.v1.BanditService/SelectArm"
+	BanditService_SelectArms_FullMethodName       = "/mab.v1.BanditService/SelectArms"
+	BanditService_Observe_FullMethodName          = "/mab.v1.BanditService/Observe"
+	BanditService_GetProbabilities_FullMethodName = "/mab.v1.BanditService/GetProbabilities"
+)
+
+// BanditServiceClient is the client API for BanditService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BanditService selects arms and observes rewards for named bandits.
+type BanditServiceClient interface {
+	// SelectArm selects an arm for a unit.
+	SelectArm(ctx context.Context, in *SelectArmRequest, opts ...grpc.CallOption) (*SelectArmResponse, error)
+	// SelectArms selects arms for a batch of units. Requests that fail have an error in their response.
+	SelectArms(ctx context.Context, in *SelectArmsRequest, opts ...grpc.CallOption) (*SelectArmsResponse, error)
+	// Observe feeds an observed reward back to a bandit.
+	Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (*ObserveResponse, error)
+	// GetProbabilities returns the reward estimates and selection probabilities for a context, without selecting an arm.
+	GetProbabilities(ctx context.Context, in *GetProbabilitiesRequest, opts ...grpc.CallOption) (*GetProbabilitiesResponse, error)
+}
+
+type banditServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBanditServiceClient(cc grpc.ClientConnInterface) BanditServiceClient {
+	return &banditServiceClient{cc}
+}
+
+func (c *banditServiceClient) SelectArm(ctx context.Context, in *SelectArmRequest, opts ...grpc.CallOption) (*SelectArmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectArmResponse)
+	err := c.cc.Invoke(ctx, BanditService_SelectArm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *banditServiceClient) SelectArms(ctx context.Context, in *SelectArmsRequest, opts ...grpc.CallOption) (*SelectArmsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectArmsResponse)
+	err := c.cc.Invoke(ctx, BanditService_SelectArms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *banditServiceClient) Observe(ctx context.Context, in *ObserveRequest, opts ...grpc.CallOption) (*ObserveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ObserveResponse)
+	err := c.cc.Invoke(ctx, BanditService_Observe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *banditServiceClient) GetProbabilities(ctx context.Context, in *GetProbabilitiesRequest, opts ...grpc.CallOption) (*GetProbabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProbabilitiesResponse)
+	err := c.cc.Invoke(ctx, BanditService_GetProbabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BanditServiceServer is the server API for BanditService service.
+// All implementations must embed UnimplementedBanditServiceServer
+// for forward compatibility.
+//
+// BanditService selects arms and observes rewards for named bandits.
+type BanditServiceServer interface {
+	// SelectArm selects an arm for a unit.
+	SelectArm(context.Context, *SelectArmRequest) (*SelectArmResponse, error)
+	// SelectArms selects arms for a batch of units. Requests that fail have an error in their response.
+	SelectArms(context.Context, *SelectArmsRequest) (*SelectArmsResponse, error)
+	// Observe feeds an observed reward back to a bandit.
+	Observe(context.Context, *ObserveRequest) (*ObserveResponse, error)
+	// GetProbabilities returns the reward estimates and selection probabilities for a context, without selecting an arm.
+	GetProbabilities(context.Context, *GetProbabilitiesRequest) (*GetProbabilitiesResponse, error)
+	mustEmbedUnimplementedBanditServiceServer()
+}
+
+// UnimplementedBanditServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBanditServiceServer struct{}
+
+func (UnimplementedBanditServiceServer) SelectArm(context.Context, *SelectArmRequest) (*SelectArmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectArm not implemented")
+}
+func (UnimplementedBanditServiceServer) SelectArms(context.Context, *SelectArmsRequest) (*SelectArmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectArms not implemented")
+}
+func (UnimplementedBanditServiceServer) Observe(context.Context, *ObserveRequest) (*ObserveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Observe not implemented")
+}
+func (UnimplementedBanditServiceServer) GetProbabilities(context.Context, *GetProbabilitiesRequest) (*GetProbabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProbabilities not implemented")
+}
+func (UnimplementedBanditServiceServer) mustEmbedUnimplementedBanditServiceServer() {}
+func (UnimplementedBanditServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeBanditServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BanditServiceServer will
+// result in compilation errors.
+type UnsafeBanditServiceServer interface {
+	mustEmbedUnimplementedBanditServiceServer()
+}
+
+func RegisterBanditServiceServer(s grpc.ServiceRegistrar, srv BanditServiceServer) {
+	// If the following call pancis, it indicates UnimplementedBanditServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BanditService_ServiceDesc, srv)
+}
+
+func _BanditService_SelectArm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectArmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BanditServiceServer).SelectArm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BanditService_SelectArm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BanditServiceServer).SelectArm(ctx, req.(*SelectArmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BanditService_SelectArms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectArmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BanditServiceServer).SelectArms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BanditService_SelectArms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BanditServiceServer).SelectArms(ctx, req.(*SelectArmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BanditService_Observe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BanditServiceServer).Observe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BanditService_Observe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BanditServiceServer).Observe(ctx, req.(*ObserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BanditService_GetProbabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProbabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BanditServiceServer).GetProbabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BanditService_GetProbabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BanditServiceServer).GetProbabilities(ctx, req.(*GetProbabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BanditService_ServiceDesc is the grpc.ServiceDesc for BanditService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BanditService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mab.v1.BanditService",
+	HandlerType: (*BanditServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SelectArm",
+			Handler:    _BanditService_SelectArm_Handler,
+		},
+		{
+			MethodName: "SelectArms",
+			Handler:    _BanditService_SelectArms_Handler,
+		},
+		{
+			MethodName: "Observe",
+			Handler:    _BanditService_Observe_Handler,
+		},
+		{
+			MethodName: "GetProbabilities",
+			Handler:    _BanditService_GetProbabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mab/v1/mab.proto",
+}
+
+const (
+	RewardService_GetRewards_FullMethodName = "/mab.v1.RewardService/GetRewards"
+)
+
+// RewardServiceClient is the client API for RewardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RewardService provides reward estimates for a bandit context.
+type RewardServiceClient interface {
+	// GetRewards returns a reward estimate for each arm.
+	GetRewards(ctx context.Context, in *GetRewardsRequest, opts ...grpc.CallOption) (*GetRewardsResponse, error)
+}
+
+type rewardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRewardServiceClient(cc grpc.ClientConnInterface) RewardServiceClient {
+	return &rewardServiceClient{cc}
+}
+
+func (c *rewardServiceClient) GetRewards(ctx context.Context, in *GetRewardsRequest, opts ...grpc.CallOption) (*GetRewardsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRewardsResponse)
+	err := c.cc.Invoke(ctx, RewardService_GetRewards_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RewardServiceServer is the server API for RewardService service.
+// All implementations must embed UnimplementedRewardServiceServer
+// for forward compatibility.
+//
+// RewardService provides reward estimates for a bandit context.
+type RewardServiceServer interface {
+	// GetRewards returns a reward estimate for each arm.
+	GetRewards(context.Context, *GetRewardsRequest) (*GetRewardsResponse, error)
+	mustEmbedUnimplementedRewardServiceServer()
+}
+
+// UnimplementedRewardServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRewardServiceServer struct{}
+
+func (UnimplementedRewardServiceServer) GetRewards(context.Context, *GetRewardsRequest) (*GetRewardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRewards not implemented")
+}
+func (UnimplementedRewardServiceServer) mustEmbedUnimplementedRewardServiceServer() {}
+func (UnimplementedRewardServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRewardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RewardServiceServer will
+// result in compilation errors.
+type UnsafeRewardServiceServer interface {
+	mustEmbedUnimplementedRewardServiceServer()
+}
+
+func RegisterRewardServiceServer(s grpc.ServiceRegistrar, srv RewardServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRewardServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RewardService_ServiceDesc, srv)
+}
+
+func _RewardService_GetRewards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRewardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RewardServiceServer).GetRewards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RewardService_GetRewards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RewardServiceServer).GetRewards(ctx, req.(*GetRewardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RewardService_ServiceDesc is the grpc.ServiceDesc for RewardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RewardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mab.v1.RewardService",
+	HandlerType: (*RewardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRewards",
+			Handler:    _RewardService_GetRewards_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mab/v1/mab.proto",
+}
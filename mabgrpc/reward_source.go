@@ -0,0 +1,103 @@
+package mabgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabgrpc/mabpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewRewardSource returns a RewardSource that gets reward estimates from a gRPC RewardService on conn, with any
+// RewardSourceOption arguments applied.
+func NewRewardSource(conn grpc.ClientConnInterface, opts ...RewardSourceOption) *RewardSource {
+	r := &RewardSource{
+		client: mabpb.NewRewardServiceClient(conn),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RewardSource is a mab.RewardSource that calls a gRPC RewardService.
+// The banditContext is sent as a protobuf Value, so it must be encodable as JSON.
+type RewardSource struct {
+	client   mabpb.RewardServiceClient
+	timeout  time.Duration
+	callOpts []grpc.CallOption
+}
+
+// RewardSourceOption allows for optional arguments to NewRewardSource.
+type RewardSourceOption func(*RewardSource)
+
+// WithTimeout sets a timeout for each call to the reward service. By default, only the deadline of the context passed
+// to GetRewards applies.
+func WithTimeout(d time.Duration) RewardSourceOption {
+	return func(r *RewardSource) {
+		r.timeout = d
+	}
+}
+
+// WithCallOptions sets grpc.CallOptions that are passed to each call to the reward service.
+func WithCallOptions(opts ...grpc.CallOption) RewardSourceOption {
+	return func(r *RewardSource) {
+		r.callOpts = append(r.callOpts, opts...)
+	}
+}
+
+// GetRewards gets the reward estimates for the banditContext from the reward service.
+// An InvalidArgument status from the reward service is returned as a mab.ErrInvalidContext error, and any other
+// failed call as a mab.ErrRewardFetch error, which wraps the gRPC status error.
+func (r *RewardSource) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	pbContext, err := ContextToProto(banditContext)
+	if err != nil {
+		return nil, mab.Errorf(mab.ErrInvalidContext, "%w", err)
+	}
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	resp, err := r.client.GetRewards(ctx, &mabpb.GetRewardsRequest{Context: pbContext}, r.callOpts...)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, mab.Errorf(mab.ErrInvalidContext, "%w", err)
+		}
+		return nil, mab.Errorf(mab.ErrRewardFetch, "%w", err)
+	}
+
+	return DistsFromProto(resp.GetRewards())
+}
+
+// NewRewardServer returns a mabpb.RewardServiceServer that serves reward estimates from source.
+// For example, to serve reward estimates from a stub:
+//	s := grpc.NewServer()
+//	mabpb.RegisterRewardServiceServer(s, mabgrpc.NewRewardServer(&mab.ContextualRewardStub{Rewards: rewards}))
+//	s.Serve(lis)
+func NewRewardServer(source mab.RewardSource) mabpb.RewardServiceServer {
+	return &rewardServer{source: source}
+}
+
+type rewardServer struct {
+	mabpb.UnimplementedRewardServiceServer
+
+	source mab.RewardSource
+}
+
+func (s *rewardServer) GetRewards(ctx context.Context, req *mabpb.GetRewardsRequest) (*mabpb.GetRewardsResponse, error) {
+	rewards, err := s.source.GetRewards(ctx, ContextFromProto(req.GetContext()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	pb, err := DistsToProto(rewards)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &mabpb.GetRewardsResponse{Rewards: pb}, nil
+}
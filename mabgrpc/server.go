@@ -0,0 +1,239 @@
+package mabgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabgrpc/mabpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultMaxBatchSize = 100
+
+// Bandits provides the bandits served by a Server. A *registry.Registry can be used directly.
+type Bandits interface {
+	Get(name string) (*mab.Bandit, bool)
+	Names() []string
+}
+
+// Single returns Bandits containing only b, with the given name.
+// Requests that do not name a bandit are served by it.
+func Single(name string, b *mab.Bandit) Bandits {
+	return single{name, b}
+}
+
+type single struct {
+	name   string
+	bandit *mab.Bandit
+}
+
+func (s single) Get(name string) (*mab.Bandit, bool) {
+	if name != s.name {
+		return nil, false
+	}
+	return s.bandit, true
+}
+
+func (s single) Names() []string { return []string{s.name} }
+
+// NewServer returns a Server for the bandits, with any ServerOption arguments applied.
+// For example, to serve all the bandits in a registry:
+//	reg := registry.New()
+//	go reg.Watch(ctx, "bandits.yaml", 10*time.Second, logError)
+//
+//	s := grpc.NewServer()
+//	mabpb.RegisterBanditServiceServer(s, mabgrpc.NewServer(reg))
+//	s.Serve(lis)
+func NewServer(bandits Bandits, opts ...ServerOption) *Server {
+	s := &Server{
+		bandits:       bandits,
+		maxBatchSize:  defaultMaxBatchSize,
+		decodeContext: DecodeContext,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Server implements mabpb.BanditServiceServer.
+// Errors are returned as gRPC statuses: unknown bandits are NotFound, invalid requests are InvalidArgument, errors
+// from a reward service are Unavailable, and timeouts and cancellations are DeadlineExceeded and Canceled.
+// In a SelectArms batch, requests that fail have an Error with the status code in their response instead.
+type Server struct {
+	mabpb.UnimplementedBanditServiceServer
+
+	bandits       Bandits
+	maxBatchSize  int
+	decodeContext ContextDecoder
+}
+
+// ServerOption allows for optional arguments to NewServer.
+type ServerOption func(*Server)
+
+// WithMaxBatchSize sets the maximum number of requests in a SelectArms batch. The default is 100.
+func WithMaxBatchSize(n int) ServerOption {
+	return func(s *Server) {
+		s.maxBatchSize = n
+	}
+}
+
+// WithContextDecoder sets the ContextDecoder used for the context of each request. The default is DecodeContext.
+// For example, to serve a linear.Model, which expects a []float64 feature vector:
+//	mabgrpc.NewServer(bandits, mabgrpc.WithContextDecoder(mabgrpc.DecodeFeatureVector))
+func WithContextDecoder(d ContextDecoder) ServerOption {
+	return func(s *Server) {
+		s.decodeContext = d
+	}
+}
+
+// SelectArm selects an arm for a unit.
+func (s *Server) SelectArm(ctx context.Context, req *mabpb.SelectArmRequest) (*mabpb.SelectArmResponse, error) {
+	return s.selectOne(ctx, req.GetBandit(), req)
+}
+
+// SelectArms selects arms for a batch of units.
+func (s *Server) SelectArms(ctx context.Context, req *mabpb.SelectArmsRequest) (*mabpb.SelectArmsResponse, error) {
+	if len(req.GetRequests()) > s.maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch has %d requests. max=%d", len(req.GetRequests()), s.maxBatchSize)
+	}
+
+	resp := &mabpb.SelectArmsResponse{Results: make([]*mabpb.SelectArmResponse, len(req.GetRequests()))}
+	for i, item := range req.GetRequests() {
+		name := item.GetBandit()
+		if name == "" {
+			name = req.GetBandit()
+		}
+		result, err := s.selectOne(ctx, name, item)
+		if err != nil {
+			st := status.Convert(err)
+			result = &mabpb.SelectArmResponse{
+				Bandit: name,
+				Error:  &mabpb.Error{Code: int32(st.Code()), Message: st.Message()},
+			}
+		}
+		resp.Results[i] = result
+	}
+	return resp, nil
+}
+
+// Observe feeds an observed reward back to a bandit.
+func (s *Server) Observe(ctx context.Context, req *mabpb.ObserveRequest) (*mabpb.ObserveResponse, error) {
+	_, bandit, err := s.lookup(req.GetBandit())
+	if err != nil {
+		return nil, err
+	}
+
+	banditContext, err := s.decodeContext(req.GetContext())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid context: %s", err)
+	}
+	if err := bandit.Observe(ctx, banditContext, int(req.GetArm()), req.GetReward(), req.GetPropensity()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &mabpb.ObserveResponse{}, nil
+}
+
+// GetProbabilities returns the reward estimates and selection probabilities for a context, without selecting an arm.
+// The bandit's overrides, holdout and arm filters are not applied, and the probabilities are computed with
+// mab.PeekProbs, so that the state of a Strategy such as a Smoother is not changed.
+func (s *Server) GetProbabilities(ctx context.Context, req *mabpb.GetProbabilitiesRequest) (*mabpb.GetProbabilitiesResponse, error) {
+	name, bandit, err := s.lookup(req.GetBandit())
+	if err != nil {
+		return nil, err
+	}
+
+	banditContext, err := s.decodeContext(req.GetContext())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid context: %s", err)
+	}
+	rewards, err := bandit.GetRewards(ctx, banditContext)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	probs, err := mab.PeekProbs(ctx, bandit.Strategy, banditContext, rewards)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	pbRewards, err := DistsToProto(rewards)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &mabpb.GetProbabilitiesResponse{Bandit: name, Version: bandit.Version, Rewards: pbRewards, Probs: probs}, nil
+}
+
+// selectOne selects an arm for the unit and context of req with the named bandit, ignoring the bandit name in req.
+func (s *Server) selectOne(ctx context.Context, name string, req *mabpb.SelectArmRequest) (*mabpb.SelectArmResponse, error) {
+	name, bandit, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	banditContext, err := s.decodeContext(req.GetContext())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid context: %s", err)
+	}
+
+	result, err := bandit.SelectArm(ctx, req.GetUnit(), banditContext)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	pb, err := ResultToProto(result)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &mabpb.SelectArmResponse{Bandit: name, Version: bandit.Version, Result: pb}, nil
+}
+
+// lookup finds the named bandit, or the only bandit if name is empty.
+func (s *Server) lookup(name string) (string, *mab.Bandit, error) {
+	if name == "" {
+		names := s.bandits.Names()
+		if len(names) != 1 {
+			sort.Strings(names)
+			return "", nil, status.Errorf(codes.InvalidArgument, "bandit must be one of %v", names)
+		}
+		name = names[0]
+	}
+	b, ok := s.bandits.Get(name)
+	if !ok {
+		return "", nil, status.Errorf(codes.NotFound, "unknown bandit %q", name)
+	}
+	return name, b, nil
+}
+
+// toStatus maps an error to the gRPC status that is returned to the client.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var non2XX *mab.ErrRewardNon2XX
+	if errors.As(err, &non2XX) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, mab.ErrInvalidContext) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, mab.ErrRewardFetch) || errors.Is(err, mab.ErrRewardParse) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	return status.Error(codes.Internal, fmt.Sprint(err))
+}
@@ -0,0 +1,127 @@
+package mabhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/stitchfix/mab"
+)
+
+// Error codes returned in the code field of an Error.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeRequestTooLarge  = "request_too_large"
+	CodeUnknownBandit    = "unknown_bandit"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeNotFound         = "not_found"
+	CodeRewardService    = "reward_service_error"
+	CodeTimeout          = "timeout"
+	CodeUnavailable      = "unavailable"
+	CodeInternal         = "internal_error"
+)
+
+// An Error is the JSON body of an error response.
+// For errors from Bandit.SelectArm, Stage is the stage of SelectArm that failed.
+// For errors from an HTTP reward service, Upstream has the reward service's status code and response body, and the
+// response has the same status code as the reward service.
+type Error struct {
+	Code     string    `json:"code"`
+	Message  string    `json:"message"`
+	Stage    string    `json:"stage,omitempty"`
+	Upstream *Upstream `json:"upstream,omitempty"`
+
+	status int
+}
+
+// Upstream describes a non-2XX response from a reward service.
+type Upstream struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Body       string `json:"body"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+type errorResponse struct {
+	Error *Error `json:"error"`
+}
+
+func newError(status int, code string, message string) *Error {
+	return &Error{Code: code, Message: message, status: status}
+}
+
+// toError maps an error to the Error that is returned to the client, and its HTTP status code.
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	e = classify(err)
+	var selectErr *mab.SelectError
+	if errors.As(err, &selectErr) {
+		e.Stage = string(selectErr.Stage)
+	}
+	return e
+}
+
+func classify(err error) *Error {
+	var non2XX *mab.ErrRewardNon2XX
+	if errors.As(err, &non2XX) {
+		e := newError(non2XX.StatusCode, CodeRewardService, err.Error())
+		e.Upstream = &Upstream{URL: non2XX.Url, StatusCode: non2XX.StatusCode, Body: non2XX.RespBody}
+		return e
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return newError(http.StatusGatewayTimeout, CodeTimeout, err.Error())
+	case errors.Is(err, context.Canceled):
+		return newError(http.StatusServiceUnavailable, CodeUnavailable, err.Error())
+	case errors.Is(err, mab.ErrInvalidContext):
+		return newError(http.StatusBadRequest, CodeBadRequest, err.Error())
+	case errors.Is(err, mab.ErrRewardFetch), errors.Is(err, mab.ErrRewardParse):
+		return newError(http.StatusBadGateway, CodeRewardService, err.Error())
+	default:
+		return newError(http.StatusInternalServerError, CodeInternal, err.Error())
+	}
+}
+
+// decodeError maps errors from reading and decoding a request body.
+func decodeError(err error) *Error {
+	// http.MaxBytesReader does not have a distinct error type in all supported Go versions.
+	if strings.Contains(err.Error(), "request body too large") {
+		return newError(http.StatusRequestEntityTooLarge, CodeRequestTooLarge, err.Error())
+	}
+	return newError(http.StatusBadRequest, CodeBadRequest, err.Error())
+}
+
+// writeJSON encodes v before writing the header, so that a value that cannot be encoded results in a 500 error
+// instead of a truncated body with the original status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		if _, ok := v.(errorResponse); ok {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeError(w, newError(http.StatusInternalServerError, CodeInternal, "failed to encode response: "+err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
+
+func writeError(w http.ResponseWriter, e *Error) {
+	status := e.status
+	if status < 400 || status > 599 {
+		status = http.StatusBadGateway
+	}
+	writeJSON(w, status, errorResponse{e})
+}
@@ -0,0 +1,309 @@
+// Package mabhttp serves bandits over HTTP with a JSON API.
+package mabhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/stitchfix/mab"
+)
+
+const (
+	defaultMaxBodyBytes = 1 << 20
+	defaultMaxBatchSize = 100
+)
+
+// Bandits provides the bandits served by a Handler. A *registry.Registry can be used directly.
+type Bandits interface {
+	Get(name string) (*mab.Bandit, bool)
+	Names() []string
+}
+
+// Single returns Bandits containing only b, with the given name.
+// Requests that do not name a bandit are served by it.
+func Single(name string, b *mab.Bandit) Bandits {
+	return single{name, b}
+}
+
+type single struct {
+	name   string
+	bandit *mab.Bandit
+}
+
+func (s single) Get(name string) (*mab.Bandit, bool) {
+	if name != s.name {
+		return nil, false
+	}
+	return s.bandit, true
+}
+
+func (s single) Names() []string { return []string{s.name} }
+
+// NewHandler returns a Handler for the bandits, with any Option arguments applied.
+// For example, to serve all the bandits in a registry:
+//	reg := registry.New()
+//	go reg.Watch(ctx, "bandits.yaml", 10*time.Second, logError)
+//
+//	http.ListenAndServe(":8080", mabhttp.NewHandler(reg))
+func NewHandler(bandits Bandits, opts ...Option) *Handler {
+	h := &Handler{
+		bandits:       bandits,
+		maxBodyBytes:  defaultMaxBodyBytes,
+		maxBatchSize:  defaultMaxBatchSize,
+		decodeContext: DecodeJSONContext,
+		mux:           http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("/select_arm", h.method(http.MethodPost, h.selectArm))
+	h.mux.HandleFunc("/select_arms", h.method(http.MethodPost, h.selectArms))
+	h.mux.HandleFunc("/observe", h.method(http.MethodPost, h.observe))
+	h.mux.HandleFunc("/health", h.method(http.MethodGet, h.health))
+	h.mux.HandleFunc("/openapi.json", h.method(http.MethodGet, h.openAPI))
+	h.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, newError(http.StatusNotFound, CodeNotFound, fmt.Sprintf("no route for %s", r.URL.Path)))
+	})
+
+	return h
+}
+
+// Handler is an http.Handler that serves bandits with the following routes:
+//	POST /select_arm    select an arm for a unit
+//	POST /select_arms   select arms for a batch of units
+//	POST /observe       feed an observed reward back to a bandit
+//	GET  /health        report the served bandits and their versions
+//	GET  /openapi.json  the OpenAPI document describing these routes
+// Errors are returned as JSON with a code and message. Errors from an HTTP reward service are passed through with the
+// reward service's status code.
+type Handler struct {
+	bandits       Bandits
+	maxBodyBytes  int64
+	maxBatchSize  int
+	decodeContext ContextDecoder
+	mux           *http.ServeMux
+}
+
+// A ContextDecoder converts the JSON-encoded context of a request into the banditContext passed to the bandit.
+type ContextDecoder func(data json.RawMessage) (interface{}, error)
+
+// DecodeJSONContext is the default ContextDecoder. It decodes the context into an interface{}, so a JSON string
+// becomes a Go string and a JSON object becomes a map[string]interface{}. A missing or null context is nil.
+func DecodeJSONContext(data json.RawMessage) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) method(method string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, newError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, fmt.Sprintf("%s requires %s", r.URL.Path, method)))
+			return
+		}
+		f(w, r)
+	}
+}
+
+// SelectArmRequest is the body of a request to /select_arm, and an item of a batch request to /select_arms.
+// Bandit can be omitted if only one bandit is served.
+type SelectArmRequest struct {
+	Bandit  string          `json:"bandit,omitempty"`
+	Unit    string          `json:"unit"`
+	Context json.RawMessage `json:"context,omitempty"`
+}
+
+// SelectArmResponse is the body of a response from /select_arm, and an item of a batch response from /select_arms.
+// In a batch response, items that failed have an Error instead of a Result.
+type SelectArmResponse struct {
+	Bandit  string      `json:"bandit"`
+	Version string      `json:"version,omitempty"`
+	Result  *mab.Result `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+}
+
+// SelectArmsRequest is the body of a request to /select_arms.
+// Bandit is used for each item that does not name its own bandit.
+type SelectArmsRequest struct {
+	Bandit   string             `json:"bandit,omitempty"`
+	Requests []SelectArmRequest `json:"requests"`
+}
+
+// SelectArmsResponse is the body of a response from /select_arms, with a response for each request in order.
+type SelectArmsResponse struct {
+	Results []SelectArmResponse `json:"results"`
+}
+
+// ObserveRequest is the body of a request to /observe.
+type ObserveRequest struct {
+	Bandit     string          `json:"bandit,omitempty"`
+	Context    json.RawMessage `json:"context,omitempty"`
+	Arm        int             `json:"arm"`
+	Reward     float64         `json:"reward"`
+	Propensity float64         `json:"propensity"`
+}
+
+// HealthResponse is the body of a response from /health, with the version of each served bandit.
+type HealthResponse struct {
+	Status  string            `json:"status"`
+	Bandits map[string]string `json:"bandits"`
+}
+
+func (h *Handler) selectArm(w http.ResponseWriter, r *http.Request) {
+	var req SelectArmRequest
+	if e := h.decode(w, r, &req); e != nil {
+		writeError(w, e)
+		return
+	}
+
+	resp, e := h.selectOne(r, req)
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) selectArms(w http.ResponseWriter, r *http.Request) {
+	var req SelectArmsRequest
+	if e := h.decode(w, r, &req); e != nil {
+		writeError(w, e)
+		return
+	}
+	if len(req.Requests) > h.maxBatchSize {
+		msg := fmt.Sprintf("batch has %d requests. max=%d", len(req.Requests), h.maxBatchSize)
+		writeError(w, newError(http.StatusRequestEntityTooLarge, CodeRequestTooLarge, msg))
+		return
+	}
+
+	resp := SelectArmsResponse{Results: make([]SelectArmResponse, len(req.Requests))}
+	for i, item := range req.Requests {
+		if item.Bandit == "" {
+			item.Bandit = req.Bandit
+		}
+		result, e := h.selectOne(r, item)
+		if e != nil {
+			result = SelectArmResponse{Bandit: item.Bandit, Error: e}
+		}
+		resp.Results[i] = result
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) selectOne(r *http.Request, req SelectArmRequest) (SelectArmResponse, *Error) {
+	name, bandit, e := h.lookup(req.Bandit)
+	if e != nil {
+		return SelectArmResponse{}, e
+	}
+
+	banditContext, err := h.decodeContext(req.Context)
+	if err != nil {
+		return SelectArmResponse{}, newError(http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid context: %s", err))
+	}
+
+	result, err := bandit.SelectArm(r.Context(), req.Unit, banditContext)
+	if err != nil {
+		return SelectArmResponse{}, toError(err)
+	}
+
+	return SelectArmResponse{Bandit: name, Version: bandit.Version, Result: &result}, nil
+}
+
+func (h *Handler) observe(w http.ResponseWriter, r *http.Request) {
+	var req ObserveRequest
+	if e := h.decode(w, r, &req); e != nil {
+		writeError(w, e)
+		return
+	}
+
+	_, bandit, e := h.lookup(req.Bandit)
+	if e != nil {
+		writeError(w, e)
+		return
+	}
+
+	banditContext, err := h.decodeContext(req.Context)
+	if err != nil {
+		writeError(w, newError(http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("invalid context: %s", err)))
+		return
+	}
+
+	if err := bandit.Observe(r.Context(), banditContext, req.Arm, req.Reward, req.Propensity); err != nil {
+		writeError(w, toError(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: "ok", Bandits: make(map[string]string)}
+	for _, name := range h.bandits.Names() {
+		if b, ok := h.bandits.Get(name); ok {
+			resp.Bandits[name] = b.Version
+		}
+	}
+	status := http.StatusOK
+	if len(resp.Bandits) == 0 {
+		resp.Status = "no bandits"
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.Copy(w, bytes.NewReader([]byte(OpenAPI)))
+}
+
+// lookup finds the named bandit, or the only bandit if name is empty.
+func (h *Handler) lookup(name string) (string, *mab.Bandit, *Error) {
+	if name == "" {
+		names := h.bandits.Names()
+		if len(names) != 1 {
+			sort.Strings(names)
+			return "", nil, newError(http.StatusBadRequest, CodeBadRequest, fmt.Sprintf("bandit must be one of %v", names))
+		}
+		name = names[0]
+	}
+	b, ok := h.bandits.Get(name)
+	if !ok {
+		return "", nil, newError(http.StatusNotFound, CodeUnknownBandit, fmt.Sprintf("unknown bandit %q", name))
+	}
+	return name, b, nil
+}
+
+// decode reads the request body, up to the size limit, and decodes it as JSON into v.
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v interface{}) *Error {
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		return decodeError(err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return newError(http.StatusBadRequest, CodeBadRequest, "request body empty")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package mabhttp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabhttp"
+	"github.com/stitchfix/mab/registry"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(r *http.Request) (*http.Response, error) { return f(r) }
+
+func newRegistry() *registry.Registry {
+	reg := registry.New()
+	reg.Set("stub", "v1", &mab.Bandit{
+		RewardSource: &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{
+			"us": {mab.Beta(10, 20), mab.Beta(20, 10)},
+		}},
+		Strategy: mab.NewEpsilonGreedy(0.1),
+		Sampler:  mab.NewSha1Sampler(),
+	})
+	reg.Set("learning", "v2", &mab.Bandit{
+		RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	})
+	failing := doerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 503, Body: ioutil.NopCloser(strings.NewReader("overloaded"))}, nil
+	})
+	reg.Set("failing", "v3", &mab.Bandit{
+		RewardSource: mab.NewHTTPSource(failing, "http://rewards", mab.ParseFunc(mab.BetaFromJSON)),
+		Strategy:     mab.NewEpsilonGreedy(0.1),
+		Sampler:      mab.NewSha1Sampler(),
+	})
+	return reg
+}
+
+func do(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) mabhttp.Error {
+	t.Helper()
+	var resp struct {
+		Error mabhttp.Error `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid error body %q: %v", rec.Body.String(), err)
+	}
+	return resp.Error
+}
+
+func TestHandler_SelectArm(t *testing.T) {
+	h := mabhttp.NewHandler(newRegistry())
+
+	rec := do(t, h, "POST", "/select_arm", `{"bandit": "stub", "unit": "12345", "context": "us"}`)
+	if rec.Code != 200 {
+		t.Fatalf("expected 200. got=%d %s", rec.Code, rec.Body)
+	}
+
+	var resp mabhttp.SelectArmResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Bandit != "stub" || resp.Version != "v1" || resp.Result == nil {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(resp.Result.Rewards) != 2 || resp.Result.Rewards[1] != mab.Beta(20, 10) {
+		t.Errorf("rewards not decoded: %v", resp.Result.Rewards)
+	}
+	if resp.Result.DecisionID == "" || resp.Result.Arm < 0 {
+		t.Errorf("unexpected result %+v", resp.Result)
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := mabhttp.NewHandler(newRegistry(), mabhttp.WithMaxBodyBytes(100))
+
+	tests := map[string]struct {
+		method, path, body string
+		status             int
+		code               string
+	}{
+		"unknown bandit":     {"POST", "/select_arm", `{"bandit": "nope", "unit": "1"}`, 404, mabhttp.CodeUnknownBandit},
+		"ambiguous bandit":   {"POST", "/select_arm", `{"unit": "1"}`, 400, mabhttp.CodeBadRequest},
+		"invalid json":       {"POST", "/select_arm", `{"bandit": `, 400, mabhttp.CodeBadRequest},
+		"unknown field":      {"POST", "/select_arm", `{"bandit": "stub", "user": "1"}`, 400, mabhttp.CodeBadRequest},
+		"empty body":         {"POST", "/select_arm", ``, 400, mabhttp.CodeBadRequest},
+		"too large":          {"POST", "/select_arm", `{"bandit": "stub", "unit": "` + strings.Repeat("1", 200) + `"}`, 413, mabhttp.CodeRequestTooLarge},
+		"wrong method":       {"GET", "/select_arm", ``, 405, mabhttp.CodeMethodNotAllowed},
+		"not found":          {"GET", "/nope", ``, 404, mabhttp.CodeNotFound},
+		"invalid context":    {"POST", "/select_arm", `{"bandit": "stub", "unit": "1", "context": "fr"}`, 400, mabhttp.CodeBadRequest},
+		"reward service 503": {"POST", "/select_arm", `{"bandit": "failing", "unit": "1"}`, 503, mabhttp.CodeRewardService},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := do(t, h, test.method, test.path, test.body)
+			if rec.Code != test.status {
+				t.Errorf("expected status %d. got=%d %s", test.status, rec.Code, rec.Body)
+			}
+			if e := decodeError(t, rec); e.Code != test.code {
+				t.Errorf("expected code %s. got=%+v", test.code, e)
+			}
+		})
+	}
+
+	rec := do(t, h, "POST", "/select_arm", `{"bandit": "failing", "unit": "1"}`)
+	e := decodeError(t, rec)
+	if e.Upstream == nil || e.Upstream.StatusCode != 503 || e.Upstream.Body != "overloaded" || e.Upstream.URL != "http://rewards" {
+		t.Errorf("expected upstream error details. got=%+v", e.Upstream)
+	}
+	if e.Stage != string(mab.StageRewards) {
+		t.Errorf("expected stage %s. got=%s", mab.StageRewards, e.Stage)
+	}
+}
+
+func TestHandler_SelectArms(t *testing.T) {
+	h := mabhttp.NewHandler(newRegistry(), mabhttp.WithMaxBatchSize(3))
+
+	body := `{"bandit": "stub", "requests": [
+		{"unit": "1", "context": "us"},
+		{"unit": "2", "context": "fr"},
+		{"bandit": "learning", "unit": "3"}
+	]}`
+	rec := do(t, h, "POST", "/select_arms", body)
+	if rec.Code != 200 {
+		t.Fatalf("expected 200. got=%d %s", rec.Code, rec.Body)
+	}
+
+	var resp mabhttp.SelectArmsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("expected 3 results. got=%d", len(resp.Results))
+	}
+	if resp.Results[0].Result == nil || resp.Results[0].Bandit != "stub" {
+		t.Errorf("unexpected first result %+v", resp.Results[0])
+	}
+	if resp.Results[1].Error == nil || resp.Results[1].Result != nil {
+		t.Errorf("expected an error for the second request. got=%+v", resp.Results[1])
+	}
+	if resp.Results[2].Result == nil || resp.Results[2].Bandit != "learning" || resp.Results[2].Version != "v2" {
+		t.Errorf("unexpected third result %+v", resp.Results[2])
+	}
+
+	rec = do(t, h, "POST", "/select_arms", `{"bandit": "stub", "requests": [{}, {}, {}, {}]}`)
+	if rec.Code != 413 {
+		t.Errorf("expected 413 for a batch that is too large. got=%d", rec.Code)
+	}
+}
+
+func TestHandler_Observe(t *testing.T) {
+	reg := newRegistry()
+	h := mabhttp.NewHandler(reg)
+
+	for i := 0; i < 3; i++ {
+		rec := do(t, h, "POST", "/observe", `{"bandit": "learning", "arm": 1, "reward": 1, "propensity": 0.5}`)
+		if rec.Code != 204 {
+			t.Fatalf("expected 204. got=%d %s", rec.Code, rec.Body)
+		}
+	}
+
+	rec := do(t, h, "POST", "/select_arm", `{"bandit": "learning", "unit": "12345"}`)
+	var resp mabhttp.SelectArmResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result.Arm != 1 || resp.Result.Rewards[1] != mab.Beta(4, 1) {
+		t.Errorf("expected observations to update the bandit. got=%+v", resp.Result)
+	}
+
+	rec = do(t, h, "POST", "/observe", `{"bandit": "learning", "arm": 1, "reward": 2}`)
+	if rec.Code != 500 {
+		t.Errorf("expected 500 for an invalid reward. got=%d", rec.Code)
+	}
+}
+
+func TestHandler_Health(t *testing.T) {
+	rec := do(t, mabhttp.NewHandler(newRegistry()), "GET", "/health", "")
+	if rec.Code != 200 {
+		t.Fatalf("expected 200. got=%d", rec.Code)
+	}
+	var resp mabhttp.HealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "ok" || resp.Bandits["stub"] != "v1" || len(resp.Bandits) != 3 {
+		t.Errorf("unexpected health %+v", resp)
+	}
+
+	rec = do(t, mabhttp.NewHandler(registry.New()), "GET", "/health", "")
+	if rec.Code != 503 {
+		t.Errorf("expected 503 with no bandits. got=%d", rec.Code)
+	}
+}
+
+func TestHandler_Single(t *testing.T) {
+	b := &mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0.1), mab.Point(0.9)}},
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+	rec := do(t, mabhttp.NewHandler(mabhttp.Single("default", b)), "POST", "/select_arm", `{"unit": "12345"}`)
+	if rec.Code != 200 {
+		t.Fatalf("expected 200. got=%d %s", rec.Code, rec.Body)
+	}
+}
+
+// unencodableDist is a Dist that cannot be encoded to JSON.
+type unencodableDist struct {
+	mab.Dist
+	C chan int
+}
+
+func TestHandler_EncodeError(t *testing.T) {
+	b := &mab.Bandit{
+		RewardSource: &mab.RewardStub{Rewards: []mab.Dist{unencodableDist{Dist: mab.Point(0.1)}, mab.Point(0.9)}},
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+	rec := do(t, mabhttp.NewHandler(mabhttp.Single("default", b)), "POST", "/select_arm", `{"unit": "12345"}`)
+	if rec.Code != 500 {
+		t.Fatalf("expected 500. got=%d %s", rec.Code, rec.Body)
+	}
+	if e := decodeError(t, rec); e.Code != mabhttp.CodeInternal {
+		t.Errorf("expected %s. got=%+v", mabhttp.CodeInternal, e)
+	}
+}
+
+func TestHandler_OpenAPI(t *testing.T) {
+	rec := do(t, mabhttp.NewHandler(newRegistry()), "GET", "/openapi.json", "")
+	if rec.Code != 200 || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/select_arm", "/select_arms", "/observe", "/health"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("missing path %s", path)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package mabhttp
+
+// OpenAPI is the OpenAPI 3 document describing the routes served by Handler. It is served at /openapi.json.
+const OpenAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "mab bandit service",
+    "version": "1"
+  },
+  "paths": {
+    "/select_arm": {
+      "post": {
+        "summary": "Select an arm for a unit",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SelectArmRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The selected arm",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SelectArmResponse"}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/select_arms": {
+      "post": {
+        "summary": "Select arms for a batch of units",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SelectArmsRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "A result or error for each request, in order",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SelectArmsResponse"}}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/observe": {
+      "post": {
+        "summary": "Feed an observed reward back to a bandit",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ObserveRequest"}}}
+        },
+        "responses": {
+          "204": {"description": "The reward was observed"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/health": {
+      "get": {
+        "summary": "Report the served bandits and their versions",
+        "responses": {
+          "200": {
+            "description": "At least one bandit is served",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HealthResponse"}}}
+          },
+          "503": {
+            "description": "No bandits are served",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HealthResponse"}}}
+          }
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "responses": {
+          "200": {"description": "The OpenAPI document", "content": {"application/json": {}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "responses": {
+      "Error": {
+        "description": "An error. Errors from an HTTP reward service have the reward service's status code.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      }
+    },
+    "schemas": {
+      "SelectArmRequest": {
+        "type": "object",
+        "required": ["unit"],
+        "properties": {
+          "bandit": {"type": "string", "description": "Can be omitted if only one bandit is served"},
+          "unit": {"type": "string"},
+          "context": {"description": "The bandit context, passed to the reward source"}
+        }
+      },
+      "SelectArmResponse": {
+        "type": "object",
+        "properties": {
+          "bandit": {"type": "string"},
+          "version": {"type": "string"},
+          "result": {"$ref": "#/components/schemas/Result"},
+          "error": {"$ref": "#/components/schemas/Error"}
+        }
+      },
+      "SelectArmsRequest": {
+        "type": "object",
+        "required": ["requests"],
+        "properties": {
+          "bandit": {"type": "string", "description": "The default bandit for requests that do not name one"},
+          "requests": {"type": "array", "items": {"$ref": "#/components/schemas/SelectArmRequest"}}
+        }
+      },
+      "SelectArmsResponse": {
+        "type": "object",
+        "properties": {
+          "results": {"type": "array", "items": {"$ref": "#/components/schemas/SelectArmResponse"}}
+        }
+      },
+      "ObserveRequest": {
+        "type": "object",
+        "required": ["arm", "reward"],
+        "properties": {
+          "bandit": {"type": "string"},
+          "context": {},
+          "arm": {"type": "integer"},
+          "reward": {"type": "number"},
+          "propensity": {"type": "number"}
+        }
+      },
+      "HealthResponse": {
+        "type": "object",
+        "properties": {
+          "status": {"type": "string"},
+          "bandits": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      },
+      "Result": {
+        "type": "object",
+        "properties": {
+          "decision_id": {"type": "string"},
+          "rewards": {"type": "array", "items": {"$ref": "#/components/schemas/Dist"}},
+          "probs": {"type": "array", "items": {"type": "number"}},
+          "arm": {"type": "integer"},
+          "filtered": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {"arm": {"type": "integer"}, "reason": {"type": "string"}}
+            }
+          },
+          "forced": {"type": "boolean"},
+          "holdout": {"type": "boolean"}
+        }
+      },
+      "Dist": {
+        "type": "object",
+        "description": "A reward distribution tagged with its type, such as {\"type\": \"beta\", \"alpha\": 2, \"beta\": 3}",
+        "required": ["type"],
+        "properties": {
+          "type": {"type": "string", "example": "beta"}
+        },
+        "additionalProperties": {"type": "number"}
+      },
+      "ErrorResponse": {
+        "type": "object",
+        "properties": {
+          "error": {"$ref": "#/components/schemas/Error"}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "code": {
+            "type": "string",
+            "enum": [
+              "bad_request",
+              "request_too_large",
+              "unknown_bandit",
+              "method_not_allowed",
+              "not_found",
+              "reward_service_error",
+              "timeout",
+              "unavailable",
+              "internal_error"
+            ]
+          },
+          "message": {"type": "string"},
+          "stage": {
+            "type": "string",
+            "enum": ["override", "rewards", "filter", "probs", "sample"],
+            "description": "The stage of SelectArm that failed"
+          },
+          "upstream": {
+            "type": "object",
+            "properties": {
+              "url": {"type": "string"},
+              "status_code": {"type": "integer"},
+              "body": {"type": "string"}
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
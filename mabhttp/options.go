@@ -0,0 +1,25 @@
+package mabhttp
+
+// Option is a function that can be passed to NewHandler to override the default settings.
+type Option func(*Handler)
+
+// WithMaxBodyBytes sets the maximum size of a request body. Larger requests get a 413 response. The default is 1MB.
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodyBytes = n
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of requests in a batch request to /select_arms. The default is 100.
+func WithMaxBatchSize(n int) Option {
+	return func(h *Handler) {
+		h.maxBatchSize = n
+	}
+}
+
+// WithContextDecoder sets the ContextDecoder used for the context of each request. The default is DecodeJSONContext.
+func WithContextDecoder(d ContextDecoder) Option {
+	return func(h *Handler) {
+		h.decodeContext = d
+	}
+}
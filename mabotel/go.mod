@@ -0,0 +1,22 @@
+module github.com/stitchfix/mab/mabotel
+
+go 1.21
+
+replace github.com/stitchfix/mab => ../
+
+require (
+	github.com/stitchfix/mab v0.2.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	golang.org/x/exp v0.0.0-20200224162631-6cc2880d07d6 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	gonum.org/v1/gonum v0.8.2 // indirect
+)
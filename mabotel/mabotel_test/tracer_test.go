@@ -0,0 +1,114 @@
+package mabotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/mabotel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingSource struct{ err error }
+
+func (s failingSource) GetRewards(context.Context, interface{}) ([]mab.Dist, error) {
+	return nil, s.err
+}
+
+func newBandit(t *testing.T, opts ...mabotel.Option) (*mab.Bandit, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	bandit := &mab.Bandit{
+		Name:            "home",
+		Version:         "abc123",
+		RewardSource:    &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0), mab.Point(1)}},
+		Strategy:        mab.NewEpsilonGreedy(0),
+		Sampler:         mab.NewSha1Sampler(),
+		Instrumentation: mabotel.NewInstrumentation(append(opts, mabotel.WithTracerProvider(provider))...),
+	}
+	return bandit, recorder
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestInstrumentation(t *testing.T) {
+	bandit, recorder := newBandit(t)
+
+	res, err := bandit.SelectArm(context.Background(), "user1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	names := make([]string, len(spans))
+	for i, s := range spans {
+		names[i] = s.Name()
+	}
+	expected := []string{"mab.rewards", "mab.probs", "mab.sample", "mab.SelectArm"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected spans %v. got=%v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected spans %v. got=%v", expected, names)
+		}
+	}
+
+	root := spans[3]
+	for _, s := range spans[:3] {
+		if s.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of mab.SelectArm", s.Name())
+		}
+	}
+
+	a := attrs(root)
+	if a[mabotel.BanditKey].AsString() != "home" || a[mabotel.VersionKey].AsString() != "abc123" {
+		t.Errorf("unexpected bandit attributes: %v", a)
+	}
+	if a[mabotel.ArmKey].AsInt64() != int64(res.Arm) || a[mabotel.DecisionIDKey].AsString() != res.DecisionID {
+		t.Errorf("unexpected result attributes: %v", a)
+	}
+	if _, ok := a[mabotel.UnitKey]; ok {
+		t.Error("unit recorded without WithUnit")
+	}
+}
+
+func TestInstrumentation_Error(t *testing.T) {
+	bandit, recorder := newBandit(t, mabotel.WithUnit())
+	bandit.RewardSource = failingSource{errors.New("unavailable")}
+
+	_, err := bandit.SelectArm(context.Background(), "user1", nil)
+	if err == nil {
+		t.Fatal("expected error but didn't get one")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans. got=%d", len(spans))
+	}
+	// the stage span has the error from the reward source, and the SelectArm span has the SelectError that wraps it
+	for i, description := range []string{"unavailable", err.Error()} {
+		s := spans[i]
+		if s.Status().Code != codes.Error || s.Status().Description != description {
+			t.Errorf("%s: unexpected status %v", s.Name(), s.Status())
+		}
+		if len(s.Events()) != 1 || s.Events()[0].Name != "exception" {
+			t.Errorf("%s: error not recorded: %v", s.Name(), s.Events())
+		}
+	}
+	if attrs(spans[1])[mabotel.UnitKey].AsString() != "user1" {
+		t.Error("unit not recorded with WithUnit")
+	}
+}
@@ -0,0 +1,113 @@
+// Package mabotel traces Bandit.SelectArm with OpenTelemetry. It is a separate module, so that the mab package does
+// not depend on OpenTelemetry.
+package mabotel
+
+import (
+	"context"
+
+	"github.com/stitchfix/mab"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/stitchfix/mab/mabotel"
+
+// Attribute keys set on spans.
+const (
+	BanditKey     = attribute.Key("mab.bandit")
+	VersionKey    = attribute.Key("mab.version")
+	UnitKey       = attribute.Key("mab.unit")
+	DecisionIDKey = attribute.Key("mab.decision_id")
+	ArmKey        = attribute.Key("mab.arm")
+	ForcedKey     = attribute.Key("mab.forced")
+	HoldoutKey    = attribute.Key("mab.holdout")
+	NumArmsKey    = attribute.Key("mab.num_arms")
+)
+
+// NewInstrumentation returns a mab.Instrumentation that traces SelectArm, with any Option arguments applied.
+// For example:
+//	bandit.Instrumentation = mabotel.NewInstrumentation(mabotel.WithTracerProvider(tp))
+func NewInstrumentation(opts ...Option) *Instrumentation {
+	i := &Instrumentation{}
+	for _, opt := range opts {
+		opt(i)
+	}
+	if i.provider == nil {
+		i.provider = otel.GetTracerProvider()
+	}
+	i.tracer = i.provider.Tracer(instrumentationName)
+	return i
+}
+
+// Instrumentation is a mab.Instrumentation that creates a "mab.SelectArm" span for each call to SelectArm, with a
+// child span for each stage, such as "mab.rewards". The context passed to the reward source is that of the
+// "mab.rewards" span, so spans created by an instrumented reward service client are its children.
+// Errors are recorded on the span of the stage that failed and on the "mab.SelectArm" span.
+type Instrumentation struct {
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	recordUnit bool
+}
+
+// Option allows for optional arguments to NewInstrumentation.
+type Option func(*Instrumentation)
+
+// WithTracerProvider sets the TracerProvider used to create spans. The default is the global TracerProvider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(i *Instrumentation) {
+		i.provider = provider
+	}
+}
+
+// WithUnit records the unit as the mab.unit attribute of SelectArm spans. Units are not recorded by default,
+// because they often identify a user.
+func WithUnit() Option {
+	return func(i *Instrumentation) {
+		i.recordUnit = true
+	}
+}
+
+// StartSelect starts the "mab.SelectArm" span.
+func (i *Instrumentation) StartSelect(ctx context.Context, bandit string, unit string) context.Context {
+	attrs := []attribute.KeyValue{BanditKey.String(bandit)}
+	if i.recordUnit {
+		attrs = append(attrs, UnitKey.String(unit))
+	}
+	ctx, _ = i.tracer.Start(ctx, "mab.SelectArm", trace.WithAttributes(attrs...))
+	return ctx
+}
+
+// EndSelect records the result and ends the "mab.SelectArm" span.
+func (i *Instrumentation) EndSelect(ctx context.Context, e mab.SelectEvent) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		VersionKey.String(e.Version),
+		DecisionIDKey.String(e.Result.DecisionID),
+		ArmKey.Int(e.Result.Arm),
+		ForcedKey.Bool(e.Result.Forced),
+		HoldoutKey.Bool(e.Result.Holdout),
+		NumArmsKey.Int(len(e.Result.Rewards)),
+	)
+	endSpan(span, e.Err)
+}
+
+// StartStage starts a span for the stage, named "mab." followed by the stage.
+func (i *Instrumentation) StartStage(ctx context.Context, stage mab.Stage) context.Context {
+	ctx, _ = i.tracer.Start(ctx, "mab."+string(stage))
+	return ctx
+}
+
+// EndStage ends the span for the stage.
+func (i *Instrumentation) EndStage(ctx context.Context, e mab.StageEvent) {
+	endSpan(trace.SpanFromContext(ctx), e.Err)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,216 @@
+// Package metrics collects Prometheus metrics from Bandit.SelectArm and serves them in the Prometheus text
+// exposition format, without depending on a Prometheus client library.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stitchfix/mab"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histogram buckets.
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Error categories returned by DefaultCategory.
+const (
+	CategoryTimeout          = "timeout"
+	CategoryCanceled         = "canceled"
+	CategoryRewardService    = "reward_service"
+	CategoryRewardParse      = "reward_parse"
+	CategoryInvalidContext   = "invalid_context"
+	CategoryInvalidDist      = "invalid_dist"
+	CategoryInvalidParameter = "invalid_parameter"
+	CategoryNotConverged     = "not_converged"
+	CategoryInvalidProbs     = "invalid_probs"
+	CategorySampler          = "sampler"
+	CategoryOther            = "other"
+)
+
+var kindCategories = map[error]string{
+	mab.ErrRewardFetch:      CategoryRewardService,
+	mab.ErrRewardParse:      CategoryRewardParse,
+	mab.ErrInvalidContext:   CategoryInvalidContext,
+	mab.ErrInvalidDist:      CategoryInvalidDist,
+	mab.ErrInvalidParameter: CategoryInvalidParameter,
+	mab.ErrNotConverged:     CategoryNotConverged,
+	mab.ErrInvalidProbs:     CategoryInvalidProbs,
+	mab.ErrSampler:          CategorySampler,
+}
+
+// DefaultCategory is the default error categorizer. Errors from context deadlines and cancellations have their own
+// categories, errors classified by mab.ErrorKind have a category for each kind, and all other errors are CategoryOther.
+func DefaultCategory(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	case errors.Is(err, context.Canceled):
+		return CategoryCanceled
+	}
+	if category, ok := kindCategories[mab.ErrorKind(err)]; ok {
+		return category
+	}
+	return CategoryOther
+}
+
+// NewCollector returns a new Collector with any Option arguments applied.
+// For example, to collect metrics from a bandit and serve them for Prometheus to scrape:
+//
+//	collector := metrics.NewCollector()
+//	bandit.Instrumentation = collector
+//
+//	http.Handle("/metrics", collector)
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
+		buckets:     DefaultBuckets,
+		categorize:  DefaultCategory,
+		selections:  make(map[string]*counter),
+		errors:      make(map[string]*counter),
+		selectTimes: make(map[string]*histogram),
+		stageTimes:  make(map[string]*histogram),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Collector is a mab.Instrumentation that collects the following metrics, labeled by bandit name:
+//
+//	mab_selections_total          counter of selected arms, labeled by arm and kind (bandit, forced or holdout)
+//	mab_errors_total              counter of failed calls to SelectArm, labeled by stage and error category
+//	mab_select_duration_seconds   histogram of the duration of calls to SelectArm
+//	mab_stage_duration_seconds    histogram of the duration of each stage of SelectArm, labeled by stage
+//
+// A Collector is also an http.Handler that serves the metrics in the Prometheus text exposition format.
+// It can be shared by many bandits, as long as they have different names.
+type Collector struct {
+	buckets    []float64
+	categorize func(error) string
+
+	mu          sync.Mutex
+	selections  map[string]*counter
+	errors      map[string]*counter
+	selectTimes map[string]*histogram
+	stageTimes  map[string]*histogram
+}
+
+// Option allows for optional arguments to NewCollector.
+type Option func(*Collector)
+
+// WithBuckets sets the upper bounds in seconds of the latency histogram buckets, in increasing order.
+// The default is DefaultBuckets.
+func WithBuckets(buckets ...float64) Option {
+	return func(c *Collector) {
+		c.buckets = buckets
+	}
+}
+
+// WithErrorCategory sets the function that assigns each error to a category for the mab_errors_total counter.
+// Categories should be a small, fixed set of values. The default is DefaultCategory.
+func WithErrorCategory(categorize func(error) string) Option {
+	return func(c *Collector) {
+		c.categorize = categorize
+	}
+}
+
+// StartSelect implements mab.Instrumentation.
+func (c *Collector) StartSelect(ctx context.Context, bandit string, unit string) context.Context {
+	return ctx
+}
+
+// EndSelect records the selected arm and the duration of the call.
+func (c *Collector) EndSelect(ctx context.Context, e mab.SelectEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.histogram(c.selectTimes, e.Bandit).observe(e.Duration.Seconds())
+
+	if e.Err != nil || e.Result.Arm < 0 {
+		return
+	}
+	kind := "bandit"
+	switch {
+	case e.Result.Forced:
+		kind = "forced"
+	case e.Result.Holdout:
+		kind = "holdout"
+	}
+	c.counter(c.selections, e.Bandit, strconv.Itoa(e.Result.Arm), kind).value++
+}
+
+// StartStage implements mab.Instrumentation.
+func (c *Collector) StartStage(ctx context.Context, stage mab.Stage) context.Context {
+	return ctx
+}
+
+// EndStage records the duration of the stage, and its error, if any.
+func (c *Collector) EndStage(ctx context.Context, e mab.StageEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.histogram(c.stageTimes, e.Bandit, string(e.Stage)).observe(e.Duration.Seconds())
+
+	if e.Err != nil {
+		c.counter(c.errors, e.Bandit, string(e.Stage), c.categorize(e.Err)).value++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+type counter struct {
+	labels []string
+	value  float64
+}
+
+type histogram struct {
+	labels []string
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func seriesKey(labels []string) string {
+	return strings.Join(labels, "\xff")
+}
+
+// counter returns the counter for the label values, creating it if necessary. c.mu must be held.
+func (c *Collector) counter(m map[string]*counter, labels ...string) *counter {
+	key := seriesKey(labels)
+	s, ok := m[key]
+	if !ok {
+		s = &counter{labels: labels}
+		m[key] = s
+	}
+	return s
+}
+
+// histogram returns the histogram for the label values, creating it if necessary. c.mu must be held.
+func (c *Collector) histogram(m map[string]*histogram, labels ...string) *histogram {
+	key := seriesKey(labels)
+	h, ok := m[key]
+	if !ok {
+		h = &histogram{labels: labels, bounds: c.buckets, counts: make([]uint64, len(c.buckets))}
+		m[key] = h
+	}
+	return h
+}
@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteTo writes the metrics to w in the Prometheus text exposition format. Series are sorted by their label
+// values, so the output is deterministic.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+
+	writeCounters(cw, "mab_selections_total", "Arms selected by Bandit.SelectArm.",
+		[]string{"bandit", "arm", "kind"}, c.selections)
+	writeCounters(cw, "mab_errors_total", "Failed calls to Bandit.SelectArm by stage and error category.",
+		[]string{"bandit", "stage", "category"}, c.errors)
+	writeHistograms(cw, "mab_select_duration_seconds", "Duration of calls to Bandit.SelectArm.",
+		[]string{"bandit"}, c.selectTimes)
+	writeHistograms(cw, "mab_stage_duration_seconds", "Duration of each stage of Bandit.SelectArm.",
+		[]string{"bandit", "stage"}, c.stageTimes)
+
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func writeCounters(w *countingWriter, name, help string, labelNames []string, series map[string]*counter) {
+	writeHeader(w, name, help, "counter")
+	for _, key := range sortedKeys(series) {
+		s := series[key]
+		w.printf("%s%s %s\n", name, formatLabels(labelNames, s.labels), formatValue(s.value))
+	}
+}
+
+func writeHistograms(w *countingWriter, name, help string, labelNames []string, series map[string]*histogram) {
+	writeHeader(w, name, help, "histogram")
+	bucketLabels := append(append([]string(nil), labelNames...), "le")
+	for _, key := range sortedKeys(series) {
+		h := series[key]
+		for i, bound := range h.bounds {
+			labels := append(append([]string(nil), h.labels...), formatValue(bound))
+			w.printf("%s_bucket%s %d\n", name, formatLabels(bucketLabels, labels), h.counts[i])
+		}
+		labels := append(append([]string(nil), h.labels...), "+Inf")
+		w.printf("%s_bucket%s %d\n", name, formatLabels(bucketLabels, labels), h.count)
+		w.printf("%s_sum%s %s\n", name, formatLabels(labelNames, h.labels), formatValue(h.sum))
+		w.printf("%s_count%s %d\n", name, formatLabels(labelNames, h.labels), h.count)
+	}
+}
+
+func writeHeader(w *countingWriter, name, help, typ string) {
+	w.printf("# HELP %s %s\n", name, help)
+	w.printf("# TYPE %s %s\n", name, typ)
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]*counter:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]*histogram:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(names, values []string) string {
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written and keeps the first error, so that writes can be chained.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) printf(format string, args ...interface{}) {
+	if c.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(c.w, format, args...)
+	c.n += int64(n)
+	c.err = err
+}
@@ -0,0 +1,104 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	collector := metrics.NewCollector(metrics.WithBuckets(0.5, 1))
+
+	bandit := &mab.Bandit{
+		Name:            "home",
+		RewardSource:    &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0), mab.Point(1)}},
+		Strategy:        mab.NewEpsilonGreedy(0),
+		Sampler:         mab.NewSha1Sampler(),
+		Instrumentation: collector,
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := bandit.SelectArm(context.Background(), fmt.Sprint(i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bandit.RewardSource = &mab.RewardStub{Rewards: []mab.Dist{mab.Point(-1)}}
+	bandit.Strategy = mab.NewProportional()
+	if _, err := bandit.SelectArm(context.Background(), "4", nil); err == nil {
+		t.Fatal("expected error from strategy but didn't get one")
+	}
+
+	var buf bytes.Buffer
+	if _, err := collector.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, expected := range []string{
+		"# TYPE mab_selections_total counter\n",
+		`mab_selections_total{bandit="home",arm="1",kind="bandit"} 3` + "\n",
+		`mab_errors_total{bandit="home",stage="probs",category="invalid_dist"} 1` + "\n",
+		"# TYPE mab_select_duration_seconds histogram\n",
+		`mab_select_duration_seconds_bucket{bandit="home",le="0.5"} 4` + "\n",
+		`mab_select_duration_seconds_bucket{bandit="home",le="+Inf"} 4` + "\n",
+		`mab_select_duration_seconds_count{bandit="home"} 4` + "\n",
+		`mab_stage_duration_seconds_count{bandit="home",stage="rewards"} 4` + "\n",
+		`mab_stage_duration_seconds_count{bandit="home",stage="sample"} 3` + "\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("output does not contain %q:\n%s", expected, out)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if rec.Body.String() != out {
+		t.Error("served metrics differ from WriteTo")
+	}
+}
+
+func TestCollector_LabelEscaping(t *testing.T) {
+	collector := metrics.NewCollector()
+	collector.EndStage(context.Background(), mab.StageEvent{
+		Bandit:   "a \"quoted\"\nname",
+		Stage:    mab.StageRewards,
+		Duration: time.Millisecond,
+		Err:      context.DeadlineExceeded,
+	})
+
+	var buf bytes.Buffer
+	if _, err := collector.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `mab_errors_total{bandit="a \"quoted\"\nname",stage="rewards",category="timeout"} 1`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("output does not contain %q:\n%s", expected, buf.String())
+	}
+}
+
+func TestDefaultCategory(t *testing.T) {
+	tests := map[string]error{
+		metrics.CategoryTimeout:       fmt.Errorf("get rewards: %w", context.DeadlineExceeded),
+		metrics.CategoryCanceled:      context.Canceled,
+		metrics.CategoryRewardService: &mab.ErrRewardNon2XX{StatusCode: 503},
+		metrics.CategoryNotConverged:  mab.Errorf(mab.ErrNotConverged, "arm 0: failed"),
+		metrics.CategoryInvalidProbs:  &mab.SelectError{Stage: mab.StageSample, Err: mab.Errorf(mab.ErrInvalidProbs, "negative weight")},
+		metrics.CategoryOther:         errors.New("failed"),
+	}
+	for expected, err := range tests {
+		if category := metrics.DefaultCategory(err); category != expected {
+			t.Errorf("%v: expected %s. got=%s", err, expected, category)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mab
+
+import (
+	"time"
+)
+
+// DiscountedPrior returns a Prior for posteriors that exponentially forget old observations.
+// Before each new reward is added, the weight of all previous rewards is multiplied by the forgetting factor gamma,
+// so that the posterior is equivalent to having observed about 1/(1-gamma) recent rewards.
+// A gamma of 1 never forgets, and smaller values adapt faster to changing rewards.
+// The prior must be one of BetaPrior, NormalPrior or NormalInverseGammaPrior, or a Prior that returns one of their
+// posterior types.
+func DiscountedPrior(prior Prior, gamma float64) Prior {
+	return func() Posterior {
+		return &DiscountedPosterior{
+			prior: prior,
+			base:  prior(),
+			gamma: gamma,
+		}
+	}
+}
+
+// DiscountedPosterior is a Posterior that down-weights older rewards by a constant factor for every new reward.
+type DiscountedPosterior struct {
+	prior Prior
+	base  Posterior
+	gamma float64
+	stats sufficientStats
+}
+
+// Update discounts the previous rewards and adds a new reward.
+// Returns an error if the reward is not valid for the underlying prior, or if gamma is not between 0 and 1.
+func (d *DiscountedPosterior) Update(reward float64) error {
+	if !(d.gamma > 0 && d.gamma <= 1) {
+		return Errorf(ErrInvalidParameter, "invalid gamma value: %v. Must be greater than 0 and at most 1", d.gamma)
+	}
+	if err := validateConjugate(d.prior, d.base, reward); err != nil {
+		return err
+	}
+	d.stats.scale(d.gamma)
+	d.stats.add(reward)
+	return nil
+}
+
+// Dist returns the posterior given the prior and the discounted rewards.
+// Validate returns an error if gamma is not between 0 and 1, or if the underlying posterior is not valid.
+func (d *DiscountedPosterior) Validate() error {
+	if !(d.gamma > 0 && d.gamma <= 1) {
+		return Errorf(ErrInvalidParameter, "invalid gamma value: %v. Must be greater than 0 and at most 1", d.gamma)
+	}
+	return ValidatePosterior(d.base)
+}
+
+func (d *DiscountedPosterior) Dist() Dist {
+	c, ok := d.base.(conjugatePosterior)
+	if !ok {
+		return d.base.Dist()
+	}
+	return c.withStats(d.stats)
+}
+
+// CountWindowPrior returns a Prior for posteriors that only use the most recent size rewards.
+// The prior must be one of BetaPrior, NormalPrior or NormalInverseGammaPrior, or a Prior that returns one of their
+// posterior types.
+func CountWindowPrior(prior Prior, size int) Prior {
+	return func() Posterior {
+		return &WindowPosterior{
+			prior: prior,
+			base:  prior(),
+			size:  size,
+		}
+	}
+}
+
+// TimeWindowPrior returns a Prior for posteriors that only use the rewards observed within the last window of time,
+// as measured by the given clock. Rewards expire even if no new rewards are observed, so an arm that stops
+// receiving traffic reverts to its prior.
+// The prior must be one of BetaPrior, NormalPrior or NormalInverseGammaPrior, or a Prior that returns one of their
+// posterior types.
+func TimeWindowPrior(prior Prior, window time.Duration, clock Clock) Prior {
+	return func() Posterior {
+		return &WindowPosterior{
+			prior:  prior,
+			base:   prior(),
+			window: window,
+			clock:  clock,
+		}
+	}
+}
+
+// WindowPosterior is a Posterior that only uses the rewards within a sliding window.
+// The window can be limited by number of rewards, by age, or both.
+// The sums of the rewards in the window are updated as rewards enter and leave it, and recomputed from the window
+// whenever as many rewards have left it as it holds, so that rounding errors do not accumulate.
+type WindowPosterior struct {
+	prior  Prior
+	base   Posterior
+	size   int
+	window time.Duration
+	clock  Clock
+
+	observations []timedReward
+	stats        sufficientStats
+	removed      int
+}
+
+type timedReward struct {
+	t      time.Time
+	reward float64
+}
+
+// Update adds a reward to the window and evicts the rewards that no longer fit in it.
+// Returns an error if the reward is not valid for the underlying prior.
+func (w *WindowPosterior) Update(reward float64) error {
+	if err := validateConjugate(w.prior, w.base, reward); err != nil {
+		return err
+	}
+
+	var now time.Time
+	if w.clock != nil {
+		now = w.clock.Now()
+	}
+
+	w.evictExpired(now)
+	if w.size > 0 && len(w.observations) >= w.size {
+		w.stats.remove(w.observations[0].reward)
+		w.observations = w.observations[1:]
+		w.removed++
+	}
+
+	w.observations = append(w.observations, timedReward{now, reward})
+	w.stats.add(reward)
+
+	if w.removed >= len(w.observations) {
+		w.recompute()
+	}
+	return nil
+}
+
+// recompute recomputes the sums from the rewards in the window, and copies them to a new slice so that the evicted
+// rewards can be garbage collected.
+func (w *WindowPosterior) recompute() {
+	w.observations = append([]timedReward(nil), w.observations...)
+	w.stats = sufficientStats{}
+	for _, o := range w.observations {
+		w.stats.add(o.reward)
+	}
+	w.removed = 0
+}
+
+// Dist returns the posterior given the prior and the rewards in the window.
+// Dist does not modify the posterior, so rewards that have expired since the last Update are excluded here
+// without being evicted.
+// Validate returns an error if the underlying posterior is not valid.
+func (w *WindowPosterior) Validate() error {
+	return ValidatePosterior(w.base)
+}
+
+func (w *WindowPosterior) Dist() Dist {
+	c, ok := w.base.(conjugatePosterior)
+	if !ok {
+		return w.base.Dist()
+	}
+
+	stats := w.stats
+	if w.window > 0 && w.clock != nil {
+		now := w.clock.Now()
+		for _, o := range w.observations {
+			if !w.expired(o, now) {
+				break
+			}
+			stats.remove(o.reward)
+		}
+	}
+
+	return c.withStats(stats)
+}
+
+func (w *WindowPosterior) evictExpired(now time.Time) {
+	if w.window <= 0 || w.clock == nil {
+		return
+	}
+	i := 0
+	for i < len(w.observations) && w.expired(w.observations[i], now) {
+		w.stats.remove(w.observations[i].reward)
+		i++
+	}
+	w.observations = w.observations[i:]
+	w.removed += i
+}
+
+func (w *WindowPosterior) expired(o timedReward, now time.Time) bool {
+	return now.Sub(o.t) > w.window
+}
+
+// validateConjugate checks that the base posterior can be used with sufficient statistics, and that the reward
+// would be accepted by a fresh posterior from the prior.
+func validateConjugate(prior Prior, base Posterior, reward float64) error {
+	if _, ok := base.(conjugatePosterior); !ok {
+		return Errorf(ErrInvalidParameter, "posterior of type %T does not support discounting or windowing", base)
+	}
+	return prior().Update(reward)
+}
@@ -2,6 +2,7 @@
 package numint
 
 import (
+	"errors"
 	"fmt"
 	"math"
 )
@@ -14,6 +15,10 @@ const (
 	defaultSubIntervals = 2
 )
 
+// ErrNotConverged is returned by Integrate when the estimate does not reach the tolerance within the maximum number of
+// iterations.
+var ErrNotConverged = errors.New("failed to converge")
+
 var defaultRule = GaussLegendre(defaultDegree)
 var defaultSubdivider = EquallySpaced(defaultSubIntervals)
 var defaultTolerance = tolerance{defaultRelTol, defaultAbsTol}
@@ -81,7 +86,7 @@ type Quadrature struct {
 // If absolute tolerance is set using WithAbsTol, only absolute tolerance is checked.
 // If relative tolerance is set using WithRelTol, only relative tolerance is checked.
 // If both absolute and relative tolerances are set using WithAbsAndRelTol, then the absolute difference must be less than *both* tolerances for the algorithm to converge.
-// If the max iteration threshold is reached without reaching the specified tolerance, Integrate returns the final result and an error.
+// If the max iteration threshold is reached without reaching the specified tolerance, Integrate returns the final result and ErrNotConverged.
 // The max iteration threshold can be specified using WithMaxIter as an argument to NewQuadrature.
 func (q Quadrature) Integrate(f func(float64) float64, a float64, b float64) (float64, error) {
 	if a == b {
@@ -113,7 +118,7 @@ func (q Quadrature) iterativeComposite(f integrand, interval Interval) (float64,
 			return result, nil
 		}
 	}
-	return result, fmt.Errorf("failed to converge")
+	return result, ErrNotConverged
 }
 
 func (q Quadrature) canConverge() bool {
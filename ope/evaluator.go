@@ -0,0 +1,287 @@
+package ope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/stitchfix/mab"
+)
+
+const (
+	defaultNumBootstrap = 1000
+	defaultConfidence   = 0.95
+	defaultSeed         = 1
+)
+
+// A RewardModel predicts the expected reward of an arm for a logged decision.
+// It is the direct-method component of the doubly robust estimator.
+type RewardModel interface {
+	Predict(d mab.Decision, arm int) float64
+}
+
+// RewardModelFunc is an adapter to allow a normal function to be used as a RewardModel.
+type RewardModelFunc func(d mab.Decision, arm int) float64
+
+func (f RewardModelFunc) Predict(d mab.Decision, arm int) float64 { return f(d, arm) }
+
+// LoggedMeans is a RewardModel that uses the Mean of the logged reward estimate for each arm.
+// Null arms are predicted to have zero reward.
+var LoggedMeans RewardModel = RewardModelFunc(func(d mab.Decision, arm int) float64 {
+	if arm < 0 || arm >= len(d.Result.Rewards) {
+		return 0
+	}
+	mean := d.Result.Rewards[arm].Mean()
+	if math.IsInf(mean, 0) || math.IsNaN(mean) {
+		return 0
+	}
+	return mean
+})
+
+// NewEvaluator returns an Evaluator for a candidate Strategy, with any Option arguments applied.
+// A Bandit can be passed as the Strategy, in which case its Strategy is used. If the Strategy is a ContextualStrategy,
+// it gets the logged bandit context of each decision. The probabilities are computed with mab.PeekProbs, so that
+// evaluating a Strategy that is also serving live traffic does not change its state:
+//	evaluator := ope.NewEvaluator(&candidate, ope.WithBootstrap(500))
+//	report, err := evaluator.Evaluate(ope.Join(decisions, rewards, 0))
+func NewEvaluator(strategy mab.Strategy, opts ...Option) *Evaluator {
+	if b, ok := strategy.(*mab.Bandit); ok {
+		strategy = b.Strategy
+	}
+	e := &Evaluator{
+		strategy:     strategy,
+		model:        LoggedMeans,
+		numBootstrap: defaultNumBootstrap,
+		confidence:   defaultConfidence,
+		seed:         defaultSeed,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Evaluator estimates the average reward that a candidate Strategy would have achieved on logged traffic.
+// For each logged decision, the candidate's selection probabilities are computed from the logged reward estimates,
+// and compared to the logged probability of the selected arm.
+type Evaluator struct {
+	strategy     mab.Strategy
+	model        RewardModel
+	numBootstrap int
+	confidence   float64
+	seed         int64
+}
+
+// An Estimate is a point estimate of the candidate's average reward with a bootstrap confidence interval.
+// Lower and Upper are NaN if bootstrapping is disabled. Fields that are not finite are omitted from the JSON encoding,
+// and decoded as NaN.
+type Estimate struct {
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+type estimateJSON struct {
+	Value *float64 `json:"value,omitempty"`
+	Lower *float64 `json:"lower,omitempty"`
+	Upper *float64 `json:"upper,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding of the estimate, without the fields that are NaN or infinite.
+func (e Estimate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(estimateJSON{finite(e.Value), finite(e.Lower), finite(e.Upper)})
+}
+
+// UnmarshalJSON decodes the JSON encoding of an estimate. Missing fields are set to NaN.
+func (e *Estimate) UnmarshalJSON(data []byte) error {
+	var v estimateJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = Estimate{orNaN(v.Value), orNaN(v.Lower), orNaN(v.Upper)}
+	return nil
+}
+
+func finite(x float64) *float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil
+	}
+	return &x
+}
+
+func orNaN(x *float64) float64 {
+	if x == nil {
+		return math.NaN()
+	}
+	return *x
+}
+
+// Report contains the off-policy estimates of a candidate Strategy's average reward, along with diagnostics.
+// IPS is the inverse propensity score estimate, SNIPS is the self-normalized IPS estimate, and DR is the doubly
+// robust estimate.
+// EffectiveSampleSize is (sum of weights)^2 / (sum of squared weights), which is close to N when the candidate is
+// similar to the logging policy, and much smaller than N when the estimates rely on a few heavily-weighted samples.
+// Skipped is the number of samples with zero logged propensity, which cannot be used.
+type Report struct {
+	N                   int      `json:"n"`
+	Skipped             int      `json:"skipped"`
+	IPS                 Estimate `json:"ips"`
+	SNIPS               Estimate `json:"snips"`
+	DR                  Estimate `json:"dr"`
+	EffectiveSampleSize float64  `json:"effective_sample_size"`
+	MaxWeight           float64  `json:"max_weight"`
+}
+
+// term holds the per-sample quantities from which all the estimates are computed.
+type term struct {
+	weight, reward, direct, predicted float64
+}
+
+// Evaluate computes the off-policy estimates for the samples.
+// Returns an error if the confidence level is not between 0 and 1, if the number of bootstrap resamples is negative,
+// if there are no usable samples, if the candidate Strategy fails to compute probabilities for a logged decision, or if
+// the candidate returns the wrong number of probabilities.
+func (e *Evaluator) Evaluate(samples []Sample) (Report, error) {
+	terms := make([]term, 0, len(samples))
+	report := Report{}
+
+	if !(e.confidence > 0 && e.confidence < 1) {
+		return report, mab.Errorf(mab.ErrInvalidParameter, "invalid confidence level: %v. Must be between 0 and 1", e.confidence)
+	}
+	if e.numBootstrap < 0 {
+		return report, mab.Errorf(mab.ErrInvalidParameter, "invalid number of bootstrap resamples: %d. Must be at least 0", e.numBootstrap)
+	}
+
+	for _, s := range samples {
+		t, ok, err := e.term(s)
+		if err != nil {
+			return report, err
+		}
+		if !ok {
+			report.Skipped++
+			continue
+		}
+		terms = append(terms, t)
+	}
+
+	if len(terms) == 0 {
+		return report, fmt.Errorf("no usable samples")
+	}
+
+	report.N = len(terms)
+
+	sumW, sumW2 := 0.0, 0.0
+	for _, t := range terms {
+		sumW += t.weight
+		sumW2 += t.weight * t.weight
+		report.MaxWeight = math.Max(report.MaxWeight, t.weight)
+	}
+	if sumW2 > 0 {
+		report.EffectiveSampleSize = sumW * sumW / sumW2
+	}
+
+	ips, snips, dr := estimates(terms)
+	report.IPS.Value, report.SNIPS.Value, report.DR.Value = ips, snips, dr
+	report.IPS.Lower, report.IPS.Upper = math.NaN(), math.NaN()
+	report.SNIPS.Lower, report.SNIPS.Upper = math.NaN(), math.NaN()
+	report.DR.Lower, report.DR.Upper = math.NaN(), math.NaN()
+
+	if e.numBootstrap > 0 {
+		e.bootstrap(terms, &report)
+	}
+
+	return report, nil
+}
+
+func (e *Evaluator) term(s Sample) (term, bool, error) {
+	d := s.Decision
+	propensity := d.Propensity()
+	if propensity <= 0 {
+		return term{}, false, nil
+	}
+
+	probs, err := mab.PeekProbs(context.Background(), e.strategy, d.BanditContext, d.Result.Rewards)
+	if err != nil {
+		return term{}, false, fmt.Errorf("decision %s: %w", d.Result.DecisionID, err)
+	}
+	if len(probs) != len(d.Result.Rewards) {
+		return term{}, false, fmt.Errorf("decision %s: expected %d probabilities. got=%d", d.Result.DecisionID, len(d.Result.Rewards), len(probs))
+	}
+
+	direct := 0.0
+	for arm, p := range probs {
+		if p > 0 {
+			direct += p * e.model.Predict(d, arm)
+		}
+	}
+
+	return term{
+		weight:    probs[d.Result.Arm] / propensity,
+		reward:    s.Reward,
+		direct:    direct,
+		predicted: e.model.Predict(d, d.Result.Arm),
+	}, true, nil
+}
+
+func estimates(terms []term) (ips, snips, dr float64) {
+	sumWR, sumW, sumDR := 0.0, 0.0, 0.0
+	for _, t := range terms {
+		sumWR += t.weight * t.reward
+		sumW += t.weight
+		sumDR += t.direct + t.weight*(t.reward-t.predicted)
+	}
+	n := float64(len(terms))
+	ips = sumWR / n
+	dr = sumDR / n
+	snips = math.NaN()
+	if sumW > 0 {
+		snips = sumWR / sumW
+	}
+	return ips, snips, dr
+}
+
+func (e *Evaluator) bootstrap(terms []term, report *Report) {
+	rng := rand.New(rand.NewSource(e.seed))
+	resample := make([]term, len(terms))
+
+	ipsVals := make([]float64, e.numBootstrap)
+	snipsVals := make([]float64, 0, e.numBootstrap)
+	drVals := make([]float64, e.numBootstrap)
+
+	for b := 0; b < e.numBootstrap; b++ {
+		for i := range resample {
+			resample[i] = terms[rng.Intn(len(terms))]
+		}
+		ips, snips, dr := estimates(resample)
+		ipsVals[b] = ips
+		drVals[b] = dr
+		if !math.IsNaN(snips) {
+			snipsVals = append(snipsVals, snips)
+		}
+	}
+
+	alpha := (1 - e.confidence) / 2
+	report.IPS.Lower, report.IPS.Upper = percentiles(ipsVals, alpha)
+	report.SNIPS.Lower, report.SNIPS.Upper = percentiles(snipsVals, alpha)
+	report.DR.Lower, report.DR.Upper = percentiles(drVals, alpha)
+}
+
+// percentiles returns the alpha and 1-alpha percentiles of vals, which is sorted in place.
+func percentiles(vals []float64, alpha float64) (float64, float64) {
+	if len(vals) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	sort.Float64s(vals)
+	return quantile(vals, alpha), quantile(vals, 1-alpha)
+}
+
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	frac := pos - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
@@ -0,0 +1,224 @@
+package ope_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/ope"
+)
+
+// loggedUniform simulates n decisions logged by a uniform random policy over two arms with the given true means.
+func loggedUniform(n int, means []float64) ([]mab.Decision, map[string]float64) {
+	rng := rand.New(rand.NewSource(42))
+	decisions := make([]mab.Decision, n)
+	rewards := make(map[string]float64)
+
+	for i := range decisions {
+		id := fmt.Sprintf("d%d", i)
+		arm := rng.Intn(len(means))
+		decisions[i] = mab.Decision{
+			Unit: id,
+			Result: mab.Result{
+				DecisionID: id,
+				Rewards:    []mab.Dist{mab.Point(means[0]), mab.Point(means[1])},
+				Probs:      []float64{0.5, 0.5},
+				Arm:        arm,
+			},
+		}
+		if rng.Float64() < means[arm] {
+			rewards[id] = 1
+		}
+	}
+
+	return decisions, rewards
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	decisions, rewards := loggedUniform(5000, []float64{0.3, 0.7})
+	samples := ope.Join(decisions, rewards, 0)
+
+	evaluator := ope.NewEvaluator(mab.NewEpsilonGreedy(0), ope.WithBootstrap(200))
+
+	report, err := evaluator.Evaluate(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.N != 5000 || report.Skipped != 0 {
+		t.Errorf("unexpected sample counts: %+v", report)
+	}
+
+	for name, est := range map[string]ope.Estimate{"ips": report.IPS, "snips": report.SNIPS, "dr": report.DR} {
+		if math.Abs(est.Value-0.7) > 0.03 {
+			t.Errorf("%s estimate not near 0.7. got=%f", name, est.Value)
+		}
+		if !(est.Lower <= est.Value && est.Value <= est.Upper) {
+			t.Errorf("%s interval does not contain estimate: %+v", name, est)
+		}
+		if est.Upper-est.Lower > 0.1 {
+			t.Errorf("%s interval too wide: %+v", name, est)
+		}
+	}
+
+	// the candidate always picks arm 1, so only about half the samples have non-zero weight
+	if math.Abs(report.EffectiveSampleSize-2500) > 100 {
+		t.Errorf("effective sample size not near 2500. got=%f", report.EffectiveSampleSize)
+	}
+	if report.MaxWeight != 2 {
+		t.Errorf("max weight not 2. got=%f", report.MaxWeight)
+	}
+}
+
+func TestEvaluator_ContextualStrategy(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	rewards := map[string][]mab.Dist{
+		"us": {mab.Point(1), mab.Point(0)},
+		"uk": {mab.Point(0), mab.Point(1)},
+	}
+
+	// the best arm depends on the context, and pays off every time
+	decisions := make([]mab.Decision, 2000)
+	observed := make(map[string]float64)
+	for i := range decisions {
+		id := fmt.Sprintf("d%d", i)
+		banditContext, best := "us", 0
+		if i%2 == 1 {
+			banditContext, best = "uk", 1
+		}
+		arm := rng.Intn(2)
+		decisions[i] = mab.Decision{
+			Unit:          id,
+			BanditContext: banditContext,
+			Result:        mab.Result{DecisionID: id, Rewards: rewards[banditContext], Probs: []float64{0.5, 0.5}, Arm: arm},
+		}
+		if arm == best {
+			observed[id] = 1
+		}
+	}
+
+	frozen := mab.ClockFunc(func() time.Time { return time.Unix(0, 0) })
+	candidate := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(frozen))
+
+	report, err := ope.NewEvaluator(candidate, ope.WithBootstrap(0)).Evaluate(ope.Join(decisions, observed, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the Smoother gets each decision's context, so it keeps the best arm of each context
+	if math.Abs(report.SNIPS.Value-1) > 1e-9 {
+		t.Errorf("snips estimate not 1. got=%f", report.SNIPS.Value)
+	}
+}
+
+func TestEvaluator_DoesNotUpdateSmoother(t *testing.T) {
+	now := time.Unix(0, 0)
+	live := mab.NewSmoother(mab.NewEpsilonGreedy(0), 0.1, time.Minute, mab.WithSmoothingClock(mab.ClockFunc(func() time.Time { return now })))
+	if _, err := live.ComputeContextualProbs(context.Background(), "us", []mab.Dist{mab.Point(1), mab.Point(0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute)
+	decisions := []mab.Decision{{
+		BanditContext: "us",
+		Result:        mab.Result{DecisionID: "d", Rewards: []mab.Dist{mab.Point(0), mab.Point(1)}, Probs: []float64{0.5, 0.5}, Arm: 1},
+	}}
+	if _, err := ope.NewEvaluator(live, ope.WithBootstrap(0)).Evaluate(ope.Join(decisions, map[string]float64{"d": 1}, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	probs, err := live.ComputeContextualProbs(context.Background(), "us", []mab.Dist{mab.Point(1), mab.Point(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if probs[0] != 1 {
+		t.Errorf("expected probabilities unchanged by the evaluation. got=%v", probs)
+	}
+}
+
+func TestEvaluator_Reproducible(t *testing.T) {
+	decisions, rewards := loggedUniform(500, []float64{0.5, 0.4})
+	samples := ope.Join(decisions, rewards, 0)
+
+	r1, err := ope.NewEvaluator(mab.NewEpsilonGreedy(0.2), ope.WithSeed(7)).Evaluate(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := ope.NewEvaluator(mab.NewEpsilonGreedy(0.2), ope.WithSeed(7)).Evaluate(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r1 != r2 {
+		t.Errorf("reports not equal with the same seed: %+v, %+v", r1, r2)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	decisions := []mab.Decision{
+		{Result: mab.Result{DecisionID: "a", Probs: []float64{1}, Arm: 0}},
+		{Result: mab.Result{DecisionID: "b", Probs: []float64{1}, Arm: 0}},
+		{Result: mab.Result{DecisionID: "c", Arm: -1}, Error: "failed"},
+		{Result: mab.Result{DecisionID: "d", Arm: 1, Forced: true}},
+	}
+
+	samples := ope.Join(decisions, map[string]float64{"a": 3}, -1)
+
+	if len(samples) != 2 || samples[0].Reward != 3 || samples[1].Reward != -1 {
+		t.Errorf("unexpected samples: %+v", samples)
+	}
+}
+
+func TestEvaluator_Error(t *testing.T) {
+	samples := []ope.Sample{
+		{Decision: mab.Decision{Result: mab.Result{Rewards: []mab.Dist{mab.Point(1)}, Probs: []float64{0}, Arm: 0}}},
+	}
+
+	if _, err := ope.NewEvaluator(mab.NewEpsilonGreedy(0.1)).Evaluate(samples); err == nil {
+		t.Error("expected error for no usable samples but didn't get one")
+	}
+
+	samples[0].Decision.Result.Probs = []float64{1}
+	if _, err := ope.NewEvaluator(mab.NewEpsilonGreedy(2)).Evaluate(samples); err == nil {
+		t.Error("expected error for invalid strategy but didn't get one")
+	}
+
+	for _, opt := range []ope.Option{ope.WithConfidence(0), ope.WithConfidence(1), ope.WithConfidence(1.5), ope.WithBootstrap(-1)} {
+		if _, err := ope.NewEvaluator(mab.NewEpsilonGreedy(0.1), opt).Evaluate(samples); !errors.Is(err, mab.ErrInvalidParameter) {
+			t.Errorf("expected ErrInvalidParameter. got=%v", err)
+		}
+	}
+}
+
+func TestReport_JSON(t *testing.T) {
+	decisions, rewards := loggedUniform(100, []float64{0.3, 0.7})
+
+	report, err := ope.NewEvaluator(mab.NewEpsilonGreedy(0), ope.WithBootstrap(0)).Evaluate(ope.Join(decisions, rewards, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the intervals are NaN without bootstrapping, and are left out of the encoding
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "lower") {
+		t.Errorf("expected no interval in %s", data)
+	}
+
+	var decoded ope.Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.IPS.Value != report.IPS.Value || !math.IsNaN(decoded.IPS.Lower) || !math.IsNaN(decoded.IPS.Upper) {
+		t.Errorf("decoded report not %+v. got=%+v", report.IPS, decoded.IPS)
+	}
+}
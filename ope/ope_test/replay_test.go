@@ -0,0 +1,172 @@
+package ope_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/ope"
+)
+
+// uniformEvents simulates n events logged by a uniform random policy with the given true means.
+func uniformEvents(n int, means []float64) []ope.Event {
+	rng := rand.New(rand.NewSource(7))
+	events := make([]ope.Event, n)
+	for i := range events {
+		arm := rng.Intn(len(means))
+		reward := 0.0
+		if rng.Float64() < means[arm] {
+			reward = 1
+		}
+		events[i] = ope.Event{Unit: fmt.Sprintf("u%d", i), Arm: arm, Reward: reward}
+	}
+	return events
+}
+
+func eventReader(events []ope.Event) ope.EventReader {
+	i := 0
+	return ope.EventReaderFunc(func() (ope.Event, error) {
+		if i >= len(events) {
+			return ope.Event{}, io.EOF
+		}
+		i++
+		return events[i-1], nil
+	})
+}
+
+func TestReplayer_Run(t *testing.T) {
+	bandit := &mab.Bandit{
+		RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+		Strategy:     mab.NewUCB(1),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	replayer := ope.NewReplayer(bandit, ope.WithInterval(500))
+
+	report, err := replayer.Run(context.Background(), eventReader(uniformEvents(20000, []float64{0.3, 0.7})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Events != 20000 {
+		t.Errorf("expected 20000 events. got=%d", report.Events)
+	}
+	if report.Matched < 9000 || report.Matched > 11000 {
+		t.Errorf("expected about half the events to match. got=%d", report.Matched)
+	}
+	if math.Abs(report.MeanReward-0.7) > 0.03 {
+		t.Errorf("mean reward not near 0.7. got=%f", report.MeanReward)
+	}
+	if report.MeanReward != report.TotalReward/float64(report.Matched) {
+		t.Errorf("mean reward inconsistent with total: %+v", report)
+	}
+
+	if len(report.Curve) == 0 {
+		t.Fatal("expected a reward curve")
+	}
+	for i, p := range report.Curve[:len(report.Curve)-1] {
+		if p.Matched != (i+1)*500 {
+			t.Errorf("curve point %d: expected %d matched. got=%d", i, (i+1)*500, p.Matched)
+		}
+	}
+	last := report.Curve[len(report.Curve)-1]
+	if last.Matched != report.Matched || last.Events != report.Events || last.MeanReward != report.MeanReward {
+		t.Errorf("last curve point does not match report: %+v", last)
+	}
+}
+
+func TestReplayer_Run_HashedLog(t *testing.T) {
+	sampler := mab.NewSha1Sampler()
+	rng := rand.New(rand.NewSource(11))
+
+	tests := []struct {
+		name string
+		unit func(i int) string
+	}{
+		{"distinct units", func(i int) string { return fmt.Sprintf("u%d", i) }},
+		{"empty units", func(int) string { return "" }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the log is written by hashing each unit over uniform probabilities, and arm 0 always pays off
+			events := make([]ope.Event, 20000)
+			for i := range events {
+				unit := test.unit(i)
+				arm, err := sampler.Sample([]float64{0.5, 0.5}, unit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if unit == "" {
+					arm = rng.Intn(2)
+				}
+				reward := 0.0
+				if arm == 0 {
+					reward = 1
+				}
+				events[i] = ope.Event{Unit: unit, Arm: arm, Reward: reward}
+			}
+
+			bandit := &mab.Bandit{
+				RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(9), mab.Point(1)}},
+				Strategy:     mab.NewProportional(),
+				Sampler:      sampler,
+			}
+
+			report, err := ope.NewReplayer(bandit).Run(context.Background(), eventReader(events))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the candidate selects arm 0 with probability 0.9, whatever the hash of the unit
+			if math.Abs(report.MeanReward-0.9) > 0.02 {
+				t.Errorf("mean reward not near 0.9. got=%f", report.MeanReward)
+			}
+		})
+	}
+}
+
+func TestReplayer_Run_Errors(t *testing.T) {
+	bandit := &mab.Bandit{
+		RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+	}
+
+	readErr := errors.New("read failed")
+	events := ope.EventReaderFunc(func() (ope.Event, error) { return ope.Event{}, readErr })
+
+	if _, err := ope.NewReplayer(bandit).Run(context.Background(), events); !errors.Is(err, readErr) {
+		t.Errorf("expected read error. got=%v", err)
+	}
+
+	bandit.RewardSource = &mab.RewardStub{}
+	_, err := ope.NewReplayer(bandit).Run(context.Background(), eventReader(uniformEvents(10, []float64{0.5, 0.5})))
+	if err == nil {
+		t.Error("expected an error from the bandit")
+	}
+}
+
+func TestSampleEvents(t *testing.T) {
+	decisions, rewards := loggedUniform(10, []float64{0.3, 0.7})
+	samples := ope.Join(decisions, rewards, 0)
+
+	events := ope.SampleEvents(samples)
+	for i, s := range samples {
+		e, err := events.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Unit != s.Decision.Unit || e.Arm != s.Decision.Result.Arm || e.Reward != s.Reward {
+			t.Errorf("event %d does not match sample: %+v", i, e)
+		}
+	}
+	if _, err := events.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF. got=%v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package ope
+
+// Option is a function that can be passed to NewEvaluator to override the default settings.
+type Option func(*Evaluator)
+
+// WithRewardModel sets the RewardModel used for the doubly robust estimate. The default is LoggedMeans.
+func WithRewardModel(m RewardModel) Option {
+	return func(e *Evaluator) {
+		e.model = m
+	}
+}
+
+// WithBootstrap sets the number of bootstrap resamples used for confidence intervals. The default is 1000.
+// Setting it to zero disables the confidence intervals.
+func WithBootstrap(n int) Option {
+	return func(e *Evaluator) {
+		e.numBootstrap = n
+	}
+}
+
+// WithConfidence sets the confidence level of the bootstrap intervals. The default is 0.95.
+func WithConfidence(level float64) Option {
+	return func(e *Evaluator) {
+		e.confidence = level
+	}
+}
+
+// WithSeed sets the seed for bootstrap resampling, so that the confidence intervals are reproducible. The default is 1.
+func WithSeed(seed int64) Option {
+	return func(e *Evaluator) {
+		e.seed = seed
+	}
+}
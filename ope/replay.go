@@ -0,0 +1,190 @@
+package ope
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/stitchfix/mab"
+)
+
+const defaultReplayInterval = 100
+
+// An Event is a logged interaction with an arm that was selected uniformly at random.
+type Event struct {
+	Unit          string      `json:"unit"`
+	BanditContext interface{} `json:"context"`
+	Arm           int         `json:"arm"`
+	Reward        float64     `json:"reward"`
+}
+
+// An EventReader streams logged events. Read returns io.EOF when there are no more events.
+type EventReader interface {
+	Read() (Event, error)
+}
+
+// EventReaderFunc is an adapter to allow a normal function to be used as an EventReader.
+type EventReaderFunc func() (Event, error)
+
+func (f EventReaderFunc) Read() (Event, error) { return f() }
+
+// SampleEvents returns an EventReader over logged samples, such as the output of Join.
+func SampleEvents(samples []Sample) EventReader {
+	i := 0
+	return EventReaderFunc(func() (Event, error) {
+		if i >= len(samples) {
+			return Event{}, io.EOF
+		}
+		s := samples[i]
+		i++
+		return Event{
+			Unit:          s.Decision.Unit,
+			BanditContext: s.Decision.BanditContext,
+			Arm:           s.Decision.Result.Arm,
+			Reward:        s.Reward,
+		}, nil
+	})
+}
+
+// NewReplayer returns a Replayer for the bandit, with any ReplayOption arguments applied.
+func NewReplayer(bandit *mab.Bandit, opts ...ReplayOption) *Replayer {
+	r := &Replayer{
+		bandit:   bandit,
+		interval: defaultReplayInterval,
+		seed:     defaultSeed,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replayer evaluates a bandit on logged traffic using the replay method of Li et al. (2011).
+// For each logged event, the bandit computes its selection probabilities for the logged unit and bandit context, and
+// an arm is drawn from them with a seeded random number generator. If the drawn arm is the logged arm, the event is
+// kept, its reward counts toward the estimate, and the reward is fed back to the bandit with Bandit.Observe. Otherwise,
+// the event is discarded.
+// The bandit's Sampler is not used to draw the arm, since the logged arm may itself have been selected by hashing the
+// same unit, in which case the two choices would be correlated and the estimate biased.
+// If the logged arms were selected uniformly at random, the mean reward of the kept events is an unbiased estimate of
+// the bandit's online performance, and about 1/K of the events are kept for K arms.
+type Replayer struct {
+	bandit   *mab.Bandit
+	interval int
+	seed     int64
+}
+
+// ReplayOption allows for optional arguments to NewReplayer
+type ReplayOption func(*Replayer)
+
+// WithInterval sets how many matched events there are between points on the reward curve. The default is 100.
+func WithInterval(n int) ReplayOption {
+	return func(r *Replayer) {
+		r.interval = n
+	}
+}
+
+// WithReplaySeed sets the seed for drawing the bandit's arms, so that replays are reproducible. The default is 1.
+func WithReplaySeed(seed int64) ReplayOption {
+	return func(r *Replayer) {
+		r.seed = seed
+	}
+}
+
+// A ReplayPoint is the state of a replay after a number of logged events.
+type ReplayPoint struct {
+	Events     int     `json:"events"`
+	Matched    int     `json:"matched"`
+	MeanReward float64 `json:"mean_reward"`
+}
+
+// ReplayReport is the result of a replay.
+// MeanReward is the estimate of the bandit's average reward, and Curve shows how the estimate evolved as the bandit
+// learned from the matched events.
+type ReplayReport struct {
+	Events      int           `json:"events"`
+	Matched     int           `json:"matched"`
+	TotalReward float64       `json:"total_reward"`
+	MeanReward  float64       `json:"mean_reward"`
+	Curve       []ReplayPoint `json:"curve"`
+}
+
+// Run replays all of the events.
+// Returns an error if reading an event fails, or if the bandit returns an error when selecting an arm or observing a
+// reward.
+func (r *Replayer) Run(ctx context.Context, events EventReader) (ReplayReport, error) {
+	var report ReplayReport
+	rng := rand.New(rand.NewSource(r.seed))
+
+	for {
+		event, err := events.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		report.Events++
+
+		res, err := r.bandit.SelectArm(ctx, event.Unit, event.BanditContext)
+		if err != nil {
+			return report, fmt.Errorf("event %d: %w", report.Events, err)
+		}
+		if res.Forced {
+			continue
+		}
+		// a non-uniform holdout has a fixed control arm and no probabilities
+		arm := res.Arm
+		if len(res.Probs) > 0 {
+			arm = sampleArm(rng, res.Probs)
+		}
+		if arm != event.Arm {
+			continue
+		}
+
+		propensity := 0.0
+		if arm >= 0 && arm < len(res.Probs) {
+			propensity = res.Probs[arm]
+		}
+		if err := r.bandit.Observe(ctx, event.BanditContext, event.Arm, event.Reward, propensity); err != nil {
+			return report, fmt.Errorf("event %d: %w", report.Events, err)
+		}
+
+		report.Matched++
+		report.TotalReward += event.Reward
+		report.MeanReward = report.TotalReward / float64(report.Matched)
+
+		if r.interval > 0 && report.Matched%r.interval == 0 {
+			report.Curve = append(report.Curve, ReplayPoint{report.Events, report.Matched, report.MeanReward})
+		}
+	}
+
+	if report.Matched > 0 && (len(report.Curve) == 0 || report.Curve[len(report.Curve)-1].Matched != report.Matched) {
+		report.Curve = append(report.Curve, ReplayPoint{report.Events, report.Matched, report.MeanReward})
+	}
+
+	return report, nil
+}
+
+// sampleArm draws an arm with the given probabilities. Returns -1 if the probabilities do not add up to more than zero.
+func sampleArm(rng *rand.Rand, probs []float64) int {
+	total := 0.0
+	for _, p := range probs {
+		total += p
+	}
+	u := rng.Float64() * total
+	arm := -1
+	for i, p := range probs {
+		if p <= 0 {
+			continue
+		}
+		arm = i
+		if u < p {
+			break
+		}
+		u -= p
+	}
+	return arm
+}
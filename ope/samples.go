@@ -0,0 +1,47 @@
+// Package ope provides off-policy evaluation of bandit strategies from logged decisions.
+package ope
+
+import (
+	"github.com/stitchfix/mab"
+)
+
+// A Sample is a logged decision joined with the reward that was observed for the selected arm.
+type Sample struct {
+	Decision mab.Decision
+	Reward   float64
+}
+
+// Join matches each decision to its observed reward by decision ID.
+// Decisions that have no entry in rewards are given the defaultReward. For example, for click-through rewards where
+// only clicks are recorded, the defaultReward is 0.
+// Decisions that failed, did not select an arm, or were forced by an override are skipped.
+func Join(decisions []mab.Decision, rewards map[string]float64, defaultReward float64) []Sample {
+	samples := make([]Sample, 0, len(decisions))
+	for _, d := range decisions {
+		if d.Error != "" || d.Result.Arm < 0 || d.Result.Forced {
+			continue
+		}
+		reward, ok := rewards[d.Result.DecisionID]
+		if !ok {
+			reward = defaultReward
+		}
+		samples = append(samples, Sample{d, reward})
+	}
+	return samples
+}
+
+// LoadDecisions reads all of the decisions from one or more JSONL decision logs, such as those written by
+// mab.JSONLLogger, in the order given.
+func LoadDecisions(paths ...string) ([]mab.Decision, error) {
+	var decisions []mab.Decision
+	for _, path := range paths {
+		err := mab.ReadDecisions(path, func(d mab.Decision) error {
+			decisions = append(decisions, d)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decisions, nil
+}
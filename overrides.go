@@ -0,0 +1,112 @@
+package mab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// An Overrider forces the arm for some units, bypassing the reward source, strategy and sampler.
+// Override returns the forced arm and true if the unit and bandit context match an override, and false otherwise.
+type Overrider interface {
+	Override(ctx context.Context, unit string, banditContext interface{}) (int, bool)
+}
+
+// OverriderFunc is an adapter to allow a normal function to be used as an Overrider.
+type OverriderFunc func(ctx context.Context, unit string, banditContext interface{}) (int, bool)
+
+func (f OverriderFunc) Override(ctx context.Context, unit string, banditContext interface{}) (int, bool) {
+	return f(ctx, unit, banditContext)
+}
+
+// An OverrideRule forces Arm for units that match all of its conditions.
+// Unit matches the unit exactly, UnitPrefix matches units that start with the prefix, and Context is a predicate on
+// the bandit context. Context can only be set programmatically. Name is an optional description of the rule.
+type OverrideRule struct {
+	Name       string                               `json:"name,omitempty"`
+	Unit       string                               `json:"unit,omitempty"`
+	UnitPrefix string                               `json:"unit_prefix,omitempty"`
+	Context    func(banditContext interface{}) bool `json:"-"`
+	Arm        int                                  `json:"arm"`
+}
+
+func (r OverrideRule) matches(unit string, banditContext interface{}) bool {
+	if r.Unit != "" && unit != r.Unit {
+		return false
+	}
+	if r.UnitPrefix != "" && !strings.HasPrefix(unit, r.UnitPrefix) {
+		return false
+	}
+	if r.Context != nil && !r.Context(banditContext) {
+		return false
+	}
+	return true
+}
+
+func (r OverrideRule) validate() error {
+	if r.Unit == "" && r.UnitPrefix == "" && r.Context == nil {
+		return fmt.Errorf("rule must set at least one of unit, unit_prefix or context")
+	}
+	if r.Arm < 0 {
+		return fmt.Errorf("arm must be >= 0. got=%d", r.Arm)
+	}
+	return nil
+}
+
+// NewOverrideRules returns an Overrider for the rules, which are checked in order. The first matching rule wins.
+// Returns an error if a rule has no conditions or a negative arm.
+// For example, to force arm 2 for internal test accounts:
+//	overrides, err := NewOverrideRules(OverrideRule{Name: "qa", UnitPrefix: "test-", Arm: 2})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	bandit.Overrides = overrides
+func NewOverrideRules(rules ...OverrideRule) (*OverrideRules, error) {
+	for i, r := range rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("override rule %d: %w", i, err)
+		}
+	}
+	return &OverrideRules{rules: rules}, nil
+}
+
+// LoadOverrideRules reads override rules from a JSON file of the form:
+//	{"rules": [{"name": "qa", "unit_prefix": "test-", "arm": 2}, {"unit": "12345", "arm": 0}]}
+func LoadOverrideRules(path string) (*OverrideRules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v struct {
+		Rules []OverrideRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to parse override rules: %w", err)
+	}
+	return NewOverrideRules(v.Rules...)
+}
+
+// OverrideRules is an Overrider that checks a list of OverrideRules in order.
+type OverrideRules struct {
+	rules []OverrideRule
+}
+
+// Override returns the arm of the first rule that matches the unit and bandit context.
+func (o *OverrideRules) Override(ctx context.Context, unit string, banditContext interface{}) (int, bool) {
+	for _, r := range o.rules {
+		if r.matches(unit, banditContext) {
+			return r.Arm, true
+		}
+	}
+	return 0, false
+}
+
+// Rules returns a copy of the rules.
+func (o *OverrideRules) Rules() []OverrideRule {
+	rules := make([]OverrideRule, len(o.rules))
+	copy(rules, o.rules)
+	return rules
+}
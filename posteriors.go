@@ -0,0 +1,259 @@
+package mab
+
+import (
+	"math"
+)
+
+// A Posterior is an online reward model for a single arm.
+// Each call to Update incorporates one observed reward, and Dist returns the current posterior reward estimate.
+// Posteriors are not safe for concurrent use. BayesianSource serializes access to the posteriors it holds.
+type Posterior interface {
+	Update(reward float64) error
+	Dist() Dist
+}
+
+// A PosteriorValidator is a Posterior that can check its own parameters.
+// All of the built-in Posteriors implement PosteriorValidator.
+type PosteriorValidator interface {
+	Validate() error
+}
+
+// ValidatePosterior returns an ErrInvalidParameter error if p has invalid parameters.
+// Posteriors that do not implement PosteriorValidator are not checked.
+func ValidatePosterior(p Posterior) error {
+	if v, ok := p.(PosteriorValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// validPositive returns an ErrInvalidParameter error if x is not finite and greater than 0.
+func validPositive(name string, x float64) error {
+	if !(x > 0) || math.IsInf(x, 1) {
+		return Errorf(ErrInvalidParameter, "%s must be finite and > 0. got=%f", name, x)
+	}
+	return nil
+}
+
+// validFinite returns an ErrInvalidParameter error if x is NaN or infinite.
+func validFinite(name string, x float64) error {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return Errorf(ErrInvalidParameter, "%s must be finite. got=%f", name, x)
+	}
+	return nil
+}
+
+// A Prior returns a new Posterior with no observations.
+// It is called by a BayesianSource whenever an arm is observed for a previously unseen bandit context.
+type Prior func() Posterior
+
+// BetaPrior returns a Prior for Beta-Bernoulli posteriors with prior parameters alpha and beta.
+// A flat prior is BetaPrior(1, 1). Alpha and beta must be finite and greater than 0.
+func BetaPrior(alpha, beta float64) Prior {
+	return func() Posterior {
+		return &BetaPosterior{Alpha: alpha, Beta: beta}
+	}
+}
+
+// BetaPosterior is the conjugate posterior for binary rewards.
+// Each reward of 1 increments Alpha and each reward of 0 increments Beta.
+// Fractional rewards between 0 and 1 are counted as partial successes.
+type BetaPosterior struct {
+	Alpha, Beta float64
+}
+
+// Update adds a reward to the posterior counts.
+// Returns an error if the reward is not between 0 and 1.
+func (b *BetaPosterior) Update(reward float64) error {
+	if !(reward >= 0 && reward <= 1) {
+		return Errorf(ErrInvalidParameter, "reward must be between 0 and 1. got=%f", reward)
+	}
+	b.Alpha += reward
+	b.Beta += 1 - reward
+	return nil
+}
+
+// Validate returns an error if Alpha or Beta are not finite and greater than 0.
+func (b *BetaPosterior) Validate() error {
+	if err := validPositive("alpha", b.Alpha); err != nil {
+		return err
+	}
+	return validPositive("beta", b.Beta)
+}
+
+// Dist returns the posterior as a BetaDist.
+func (b *BetaPosterior) Dist() Dist {
+	return Beta(b.Alpha, b.Beta)
+}
+
+// NormalPrior returns a Prior for Normal-Normal posteriors with a known observation noise.
+// The prior on the mean reward is Normal(mu, sigma) and each reward is assumed to be drawn from Normal(mean, noiseSigma).
+// Mu must be finite, and sigma and noiseSigma must be finite and greater than 0.
+func NormalPrior(mu, sigma, noiseSigma float64) Prior {
+	return func() Posterior {
+		return &NormalPosterior{
+			Mu:         mu,
+			Sigma:      sigma,
+			NoiseSigma: noiseSigma,
+		}
+	}
+}
+
+// NormalPosterior is the conjugate posterior for the mean of normally-distributed rewards with known variance.
+type NormalPosterior struct {
+	Mu, Sigma  float64
+	NoiseSigma float64
+}
+
+// Update incorporates a reward into the posterior mean and standard deviation.
+// Returns an error if the reward is not finite or if the noise standard deviation is not positive.
+func (n *NormalPosterior) Update(reward float64) error {
+	if math.IsNaN(reward) || math.IsInf(reward, 0) {
+		return Errorf(ErrInvalidParameter, "reward must be finite. got=%f", reward)
+	}
+	if n.NoiseSigma <= 0 {
+		return Errorf(ErrInvalidParameter, "noise sigma must be > 0. got=%f", n.NoiseSigma)
+	}
+
+	priorPrecision := 1 / (n.Sigma * n.Sigma)
+	noisePrecision := 1 / (n.NoiseSigma * n.NoiseSigma)
+	precision := priorPrecision + noisePrecision
+
+	n.Mu = (priorPrecision*n.Mu + noisePrecision*reward) / precision
+	n.Sigma = math.Sqrt(1 / precision)
+	return nil
+}
+
+// Validate returns an error if Mu is not finite, or if Sigma or NoiseSigma are not finite and greater than 0.
+func (n *NormalPosterior) Validate() error {
+	if err := validFinite("mu", n.Mu); err != nil {
+		return err
+	}
+	if err := validPositive("sigma", n.Sigma); err != nil {
+		return err
+	}
+	return validPositive("noise sigma", n.NoiseSigma)
+}
+
+// Dist returns the posterior on the mean reward as a NormalDist.
+func (n *NormalPosterior) Dist() Dist {
+	return Normal(n.Mu, n.Sigma)
+}
+
+// NormalInverseGammaPrior returns a Prior for normally-distributed rewards with unknown mean and variance.
+// The prior on the mean is centered at mu with a weight of kappa pseudo-observations,
+// and the prior on the variance is an inverse gamma distribution with shape alpha and scale beta.
+// Mu must be finite, and kappa, alpha and beta must be finite and greater than 0.
+func NormalInverseGammaPrior(mu, kappa, alpha, beta float64) Prior {
+	return func() Posterior {
+		return &NormalInverseGammaPosterior{
+			Mu:    mu,
+			Kappa: kappa,
+			Alpha: alpha,
+			Beta:  beta,
+		}
+	}
+}
+
+// NormalInverseGammaPosterior is the conjugate posterior for normally-distributed rewards with unknown mean and variance.
+// The marginal posterior of the mean is a Student's t distribution. Dist approximates it with a NormalDist having the
+// same location and scale, which is accurate once more than a handful of rewards have been observed.
+type NormalInverseGammaPosterior struct {
+	Mu, Kappa, Alpha, Beta float64
+}
+
+// Update incorporates a reward into the posterior parameters.
+// Returns an error if the reward is not finite.
+func (n *NormalInverseGammaPosterior) Update(reward float64) error {
+	if math.IsNaN(reward) || math.IsInf(reward, 0) {
+		return Errorf(ErrInvalidParameter, "reward must be finite. got=%f", reward)
+	}
+
+	kappa := n.Kappa + 1
+	mu := (n.Kappa*n.Mu + reward) / kappa
+	n.Beta += n.Kappa * (reward - n.Mu) * (reward - n.Mu) / (2 * kappa)
+	n.Alpha += 0.5
+	n.Mu = mu
+	n.Kappa = kappa
+	return nil
+}
+
+// Validate returns an error if Mu is not finite, or if Kappa, Alpha or Beta are not finite and greater than 0.
+func (n *NormalInverseGammaPosterior) Validate() error {
+	if err := validFinite("mu", n.Mu); err != nil {
+		return err
+	}
+	if err := validPositive("kappa", n.Kappa); err != nil {
+		return err
+	}
+	if err := validPositive("alpha", n.Alpha); err != nil {
+		return err
+	}
+	return validPositive("beta", n.Beta)
+}
+
+// Dist returns a normal approximation to the marginal posterior of the mean reward.
+func (n *NormalInverseGammaPosterior) Dist() Dist {
+	return Normal(n.Mu, math.Sqrt(n.Beta/(n.Alpha*n.Kappa)))
+}
+
+// sufficientStats are the weighted count, sum and sum of squares of the observed rewards.
+type sufficientStats struct {
+	n, sum, sumSq float64
+}
+
+func (s *sufficientStats) add(reward float64) {
+	s.n++
+	s.sum += reward
+	s.sumSq += reward * reward
+}
+
+func (s *sufficientStats) remove(reward float64) {
+	s.n--
+	s.sum -= reward
+	s.sumSq -= reward * reward
+}
+
+func (s *sufficientStats) scale(f float64) {
+	s.n *= f
+	s.sum *= f
+	s.sumSq *= f
+}
+
+// A conjugatePosterior can compute the posterior that results from combining itself, as a prior, with a set of
+// sufficient statistics. This allows observations to be down-weighted or removed, which is not possible with Update.
+type conjugatePosterior interface {
+	Posterior
+	withStats(s sufficientStats) Dist
+}
+
+func (b *BetaPosterior) withStats(s sufficientStats) Dist {
+	return Beta(b.Alpha+s.sum, b.Beta+s.n-s.sum)
+}
+
+func (n *NormalPosterior) withStats(s sufficientStats) Dist {
+	priorPrecision := 1 / (n.Sigma * n.Sigma)
+	noisePrecision := 1 / (n.NoiseSigma * n.NoiseSigma)
+	precision := priorPrecision + s.n*noisePrecision
+
+	mu := (priorPrecision*n.Mu + noisePrecision*s.sum) / precision
+	return Normal(mu, math.Sqrt(1/precision))
+}
+
+func (n *NormalInverseGammaPosterior) withStats(s sufficientStats) Dist {
+	if s.n <= 0 {
+		return n.Dist()
+	}
+
+	mean := s.sum / s.n
+	sumSqDev := math.Max(0, s.sumSq-s.sum*mean)
+
+	kappa := n.Kappa + s.n
+	post := NormalInverseGammaPosterior{
+		Mu:    (n.Kappa*n.Mu + s.sum) / kappa,
+		Kappa: kappa,
+		Alpha: n.Alpha + s.n/2,
+		Beta:  n.Beta + sumSqDev/2 + n.Kappa*s.n*(mean-n.Mu)*(mean-n.Mu)/(2*kappa),
+	}
+	return post.Dist()
+}
@@ -1,9 +1,6 @@
 package mab
 
-import (
-	"fmt"
-	"math"
-)
+import "math"
 
 func NewProportional() *Proportional {
 	return &Proportional{}
@@ -29,7 +26,7 @@ func (p *Proportional) ComputeProbs(rewards []Dist) ([]float64, error) {
 		default:
 			p.meanRewards[i] = mean
 		case mean > math.Inf(-1) && mean < 0:
-			return nil, fmt.Errorf("negative mean reward")
+			return nil, Errorf(ErrInvalidDist, "negative mean reward")
 		case math.IsInf(mean, -1): // indicates a Null distribution
 			p.meanRewards[i] = 0
 		}
@@ -42,7 +39,7 @@ func (p Proportional) computeProbs() ([]float64, error) {
 	norm := 0.0
 	for _, r := range p.meanRewards {
 		if r < 0 {
-			return nil, fmt.Errorf("negative mean reward: %+v", r)
+			return nil, Errorf(ErrInvalidDist, "negative mean reward: %+v", r)
 		}
 		norm += r
 	}
@@ -0,0 +1,203 @@
+// Package registry holds named Bandits that can be reconfigured while serving traffic.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/config"
+)
+
+// New returns an empty Registry with any Option arguments applied.
+func New(opts ...Option) *Registry {
+	r := &Registry{
+		builder: config.NewBuilder(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.bandits.Store(map[string]*mab.Bandit{})
+	return r
+}
+
+// A Registry holds named Bandits.
+// Bandits are replaced atomically: a call to SelectArm that is in flight when a bandit is replaced finishes with the
+// bandit it started with, and every later call uses the replacement.
+// Each bandit has a version, which is recorded in its logged decisions. Bandits built from configuration are versioned
+// by a hash of their configuration, so a reload only replaces the bandits whose configuration changed, and the learned
+// state of the others is kept.
+type Registry struct {
+	builder *config.Builder
+	logger  mab.DecisionLogger
+
+	mu      sync.Mutex   // serializes writers
+	bandits atomic.Value // map[string]*mab.Bandit, never modified after it is stored
+}
+
+// Option is a function that can be passed to New to override the default settings.
+type Option func(*Registry)
+
+// WithBuilder sets the Builder used to build bandits from configuration. The default is config.NewBuilder().
+func WithBuilder(b *config.Builder) Option {
+	return func(r *Registry) {
+		r.builder = b
+	}
+}
+
+// WithLogger sets a DecisionLogger for every bandit added to the registry that does not already have one.
+func WithLogger(l mab.DecisionLogger) Option {
+	return func(r *Registry) {
+		r.logger = l
+	}
+}
+
+func (r *Registry) load() map[string]*mab.Bandit {
+	return r.bandits.Load().(map[string]*mab.Bandit)
+}
+
+// Get returns the current Bandit with the given name.
+// The Bandit must not be modified, since it may be in use by other goroutines.
+func (r *Registry) Get(name string) (*mab.Bandit, bool) {
+	b, ok := r.load()[name]
+	return b, ok
+}
+
+// Names returns the names of all the bandits in sorted order.
+func (r *Registry) Names() []string {
+	bandits := r.load()
+	names := make([]string, 0, len(bandits))
+	for name := range bandits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Version returns the version of the named bandit, or false if there is no such bandit.
+func (r *Registry) Version(name string) (string, bool) {
+	b, ok := r.Get(name)
+	if !ok {
+		return "", false
+	}
+	return b.Version, true
+}
+
+// Versions returns the version of every bandit by name.
+func (r *Registry) Versions() map[string]string {
+	bandits := r.load()
+	versions := make(map[string]string, len(bandits))
+	for name, b := range bandits {
+		versions[name] = b.Version
+	}
+	return versions
+}
+
+// Set adds or replaces the named bandit. The registry takes ownership of b, and sets its Name and Version.
+func (r *Registry) Set(name, version string, b *mab.Bandit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bandits := r.copyBandits()
+	bandits[name] = r.prepare(name, version, b)
+	r.bandits.Store(bandits)
+}
+
+// Remove removes the named bandit.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bandits := r.copyBandits()
+	delete(bandits, name)
+	r.bandits.Store(bandits)
+}
+
+// Apply builds the bandits whose version differs from the current one, and replaces the registry's bandits with the
+// configured set in a single atomic swap. Bandits that are not in cfgs are removed.
+// If any bandit fails to build, the registry is not changed and a *config.FieldError is returned.
+// Returns the names of the bandits that were added or replaced.
+func (r *Registry) Apply(cfgs map[string]config.Config) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.load()
+	changed := make(map[string]config.Config)
+	for name, cfg := range cfgs {
+		if b, ok := current[name]; !ok || b.Version != config.Version(cfg) {
+			changed[name] = cfg
+		}
+	}
+
+	built, err := r.builder.BuildAll(changed)
+	if err != nil {
+		return nil, err
+	}
+
+	bandits := make(map[string]*mab.Bandit, len(cfgs))
+	for name := range cfgs {
+		if b, ok := built[name]; ok {
+			bandits[name] = r.prepare(name, b.Version, b)
+		} else {
+			bandits[name] = current[name]
+		}
+	}
+	r.bandits.Store(bandits)
+
+	updated := make([]string, 0, len(built))
+	for name := range built {
+		updated = append(updated, name)
+	}
+	sort.Strings(updated)
+	return updated, nil
+}
+
+// LoadFile reads bandit configurations with config.LoadBandits and applies them.
+func (r *Registry) LoadFile(path string) ([]string, error) {
+	cfgs, err := config.LoadBandits(path)
+	if err != nil {
+		return nil, err
+	}
+	return r.Apply(cfgs)
+}
+
+// SelectArm calls SelectArm on the named bandit.
+// Returns an error if there is no such bandit.
+func (r *Registry) SelectArm(ctx context.Context, name string, unit string, banditContext interface{}) (mab.Result, error) {
+	b, ok := r.Get(name)
+	if !ok {
+		return mab.Result{Arm: -1}, fmt.Errorf("unknown bandit %q", name)
+	}
+	return b.SelectArm(ctx, unit, banditContext)
+}
+
+// Observe calls Observe on the named bandit.
+// Returns an error if there is no such bandit.
+func (r *Registry) Observe(ctx context.Context, name string, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	b, ok := r.Get(name)
+	if !ok {
+		return fmt.Errorf("unknown bandit %q", name)
+	}
+	return b.Observe(ctx, banditContext, arm, reward, propensity)
+}
+
+func (r *Registry) copyBandits() map[string]*mab.Bandit {
+	current := r.load()
+	bandits := make(map[string]*mab.Bandit, len(current)+1)
+	for name, b := range current {
+		bandits[name] = b
+	}
+	return bandits
+}
+
+func (r *Registry) prepare(name, version string, b *mab.Bandit) *mab.Bandit {
+	b.Name = name
+	b.Version = version
+	if b.Logger == nil {
+		b.Logger = r.logger
+	}
+	return b
+}
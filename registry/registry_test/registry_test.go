@@ -0,0 +1,282 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/config"
+	"github.com/stitchfix/mab/registry"
+)
+
+func banditConfig(t *testing.T, epsilon float64) config.Config {
+	t.Helper()
+	data := fmt.Sprintf(`{
+		"reward_source": {"type": "stub", "rewards": [{"type": "point", "mu": 0.2}, {"type": "point", "mu": 0.8}]},
+		"strategy": {"type": "epsilon_greedy", "epsilon": %v}
+	}`, epsilon)
+	cfg, err := config.ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestRegistry_Apply(t *testing.T) {
+	var mu sync.Mutex
+	var decisions []mab.Decision
+	logger := mab.DecisionLoggerFunc(func(ctx context.Context, d mab.Decision) {
+		mu.Lock()
+		defer mu.Unlock()
+		decisions = append(decisions, d)
+	})
+
+	r := registry.New(registry.WithLogger(logger))
+
+	updated, err := r.Apply(map[string]config.Config{
+		"homepage": banditConfig(t, 0.1),
+		"checkout": banditConfig(t, 0.2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(updated) != "[checkout homepage]" || fmt.Sprint(r.Names()) != "[checkout homepage]" {
+		t.Errorf("unexpected bandits: updated=%v names=%v", updated, r.Names())
+	}
+
+	homepage, _ := r.Get("homepage")
+	checkout, _ := r.Get("checkout")
+	homepageVersion, _ := r.Version("homepage")
+	if homepageVersion != config.Version(banditConfig(t, 0.1)) {
+		t.Errorf("unexpected version %s", homepageVersion)
+	}
+
+	res, err := r.SelectArm(context.Background(), "homepage", "12345", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Probs[1] < 0.9 {
+		t.Errorf("unexpected probabilities %v", res.Probs)
+	}
+	if len(decisions) != 1 || decisions[0].Bandit != "homepage" || decisions[0].Version != homepageVersion {
+		t.Errorf("expected a decision logged with the bandit name and version. got=%+v", decisions)
+	}
+
+	// only the changed bandit is replaced, and removed bandits are dropped
+	updated, err = r.Apply(map[string]config.Config{
+		"homepage": banditConfig(t, 0.1),
+		"search":   banditConfig(t, 0.3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(updated) != "[search]" || fmt.Sprint(r.Names()) != "[homepage search]" {
+		t.Errorf("unexpected bandits: updated=%v names=%v", updated, r.Names())
+	}
+	if b, _ := r.Get("homepage"); b != homepage {
+		t.Error("unchanged bandit was replaced")
+	}
+	if _, ok := r.Get("checkout"); ok {
+		t.Error("removed bandit is still registered")
+	}
+	if checkout.Name != "checkout" {
+		t.Error("removed bandit should be unaffected")
+	}
+
+	// a bad configuration leaves the registry unchanged
+	bad := banditConfig(t, 0.1)
+	bad.Strategy, _ = config.NewComponent("epsilon_greedy", map[string]float64{"epsilon": 5})
+	_, err = r.Apply(map[string]config.Config{"homepage": bad})
+	var fe *config.FieldError
+	if !errors.As(err, &fe) || fe.Field != "bandits.homepage.strategy.epsilon" {
+		t.Errorf("expected FieldError for bandits.homepage.strategy.epsilon. got=%v", err)
+	}
+	if fmt.Sprint(r.Names()) != "[homepage search]" {
+		t.Errorf("registry changed after failed apply: %v", r.Names())
+	}
+
+	if _, err := r.SelectArm(context.Background(), "checkout", "12345", nil); err == nil {
+		t.Error("expected error for unknown bandit")
+	}
+	if err := r.Observe(context.Background(), "checkout", nil, 0, 1, 0.5); err == nil {
+		t.Error("expected error for unknown bandit")
+	}
+}
+
+func TestRegistry_Set_Concurrent(t *testing.T) {
+	r := registry.New()
+	newBandit := func(best int) *mab.Bandit {
+		rewards := []mab.Dist{mab.Point(0), mab.Point(0)}
+		rewards[best] = mab.Point(1)
+		return &mab.Bandit{
+			RewardSource: &mab.RewardStub{Rewards: rewards},
+			Strategy:     mab.NewEpsilonGreedy(0),
+			Sampler:      mab.NewSha1Sampler(),
+		}
+	}
+	r.Set("b", "v0", newBandit(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if _, err := r.SelectArm(context.Background(), "b", fmt.Sprint(i), nil); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	for v := 1; v <= 100; v++ {
+		r.Set("b", fmt.Sprintf("v%d", v), newBandit(v%2))
+	}
+	cancel()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if v, _ := r.Version("b"); v != "v100" {
+		t.Errorf("expected version v100. got=%s", v)
+	}
+	if fmt.Sprint(r.Versions()) != "map[b:v100]" {
+		t.Errorf("unexpected versions %v", r.Versions())
+	}
+
+	r.Remove("b")
+	if len(r.Names()) != 0 {
+		t.Errorf("expected no bandits. got=%v", r.Names())
+	}
+}
+
+func TestRegistry_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bandits.yaml")
+	write := func(epsilon string, mtime time.Time) {
+		data := `
+bandits:
+  homepage:
+    reward_source:
+      type: stub
+      rewards: [{type: point, mu: 0.2}, {type: point, mu: 0.8}]
+    strategy:
+      type: epsilon_greedy
+      epsilon: ` + epsilon + "\n"
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("0.1", start)
+
+	r := registry.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloadErrs := make(chan error, 10)
+	done := make(chan error)
+	go func() {
+		done <- r.Watch(ctx, path, 5*time.Millisecond, func(err error) { reloadErrs <- err })
+	}()
+
+	waitForVersion := func(changed func(string) bool) string {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if v, ok := r.Version("homepage"); ok && changed(v) {
+				return v
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatal("timed out waiting for reload")
+		return ""
+	}
+
+	first := waitForVersion(func(string) bool { return true })
+
+	write("0.3", start.Add(time.Minute))
+	second := waitForVersion(func(v string) bool { return v != first })
+
+	write("3", start.Add(2*time.Minute))
+	select {
+	case err := <-reloadErrs:
+		var fe *config.FieldError
+		if !errors.As(err, &fe) {
+			t.Errorf("expected a FieldError. got=%v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload error")
+	}
+	if v, _ := r.Version("homepage"); v != second {
+		t.Error("bad configuration should not replace the bandit")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegistry_Watch_NoPolling(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bandits.yaml")
+	data := `
+bandits:
+  homepage:
+    reward_source:
+      type: stub
+      rewards: [{type: point, mu: 0.2}, {type: point, mu: 0.8}]
+    strategy:
+      type: epsilon_greedy
+      epsilon: 0.1
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := registry.New()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// a zero interval only reloads on SIGHUP, instead of panicking
+	if err := r.Watch(ctx, path, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Version("homepage"); !ok {
+		t.Error("expected the initial load")
+	}
+}
+
+func TestRegistry_Watch_InitialError(t *testing.T) {
+	r := registry.New()
+	if err := r.Watch(context.Background(), "/does/not/exist.yaml", time.Second, nil); err == nil {
+		t.Error("expected error")
+	}
+}
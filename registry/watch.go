@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Watch loads the bandit configuration file at path, and then reloads it whenever its modification time changes,
+// checking every interval, or when the process receives SIGHUP. It blocks until ctx is done.
+// If interval is not positive, the modification time is not polled, and the file is only reloaded on SIGHUP.
+// The initial load must succeed, or Watch returns its error. Errors from later reloads are passed to onError, which may
+// be nil, and the registry keeps serving the last good configuration.
+func (r *Registry) Watch(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	if onError == nil {
+		onError = func(error) {}
+	}
+
+	modTime, err := r.loadWatched(path)
+	if err != nil {
+		return err
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time // nil never fires
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			if t, err := r.loadWatched(path); err != nil {
+				onError(err)
+			} else {
+				modTime = t
+			}
+		case <-tick:
+			info, err := os.Stat(path)
+			if err != nil {
+				onError(err)
+				continue
+			}
+			if info.ModTime().Equal(modTime) {
+				continue
+			}
+			if t, err := r.loadWatched(path); err != nil {
+				onError(err)
+				modTime = info.ModTime() // don't retry a bad file until it changes again
+			} else {
+				modTime = t
+			}
+		}
+	}
+}
+
+// loadWatched loads the file and returns the modification time it had before it was read.
+func (r *Registry) loadWatched(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if _, err := r.LoadFile(path); err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
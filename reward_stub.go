@@ -1,9 +1,6 @@
 package mab
 
-import (
-	"context"
-	"fmt"
-)
+import "context"
 
 // RewardStub is a static non-contextual RewardSource that can be used for testing and development.
 type RewardStub struct {
@@ -25,13 +22,13 @@ type ContextualRewardStub struct {
 func (c *ContextualRewardStub) GetRewards(ctx context.Context, banditContext interface{}) ([]Dist, error) {
 	key, ok := banditContext.(string)
 	if !ok {
-		return nil, fmt.Errorf("banditContext must be a string")
+		return nil, Errorf(ErrInvalidContext, "banditContext must be a string")
 	}
 
 	val, ok := c.Rewards[key]
 
 	if !ok {
-		return nil, fmt.Errorf("no distributions for %s", val)
+		return nil, Errorf(ErrInvalidContext, "no distributions for %s", key)
 	}
 
 	return val, nil
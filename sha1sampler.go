@@ -3,6 +3,7 @@ package mab
 import (
 	"crypto/sha1"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -20,7 +21,7 @@ type Sha1Sampler struct {
 }
 
 // Sample returns the selected arm for a given set of weights and input unit.
-// An error is returned if any negative weight is encountered.
+// An error is returned if any weight is negative, NaN or infinite.
 func (s *Sha1Sampler) Sample(weights []float64, unit string) (int, error) {
 
 	checkSum := sha1.Sum([]byte(unit))
@@ -44,16 +45,26 @@ func (s *Sha1Sampler) sum(weights []float64) float64 {
 }
 
 func (s *Sha1Sampler) getIndex(weights []float64, bucket int) (int, error) {
+	for _, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return -1, Errorf(ErrInvalidProbs, "weights must be finite. got=%f", w)
+		}
+		if w < 0 {
+			return -1, Errorf(ErrInvalidProbs, "negative weight")
+		}
+	}
+
 	sumWeights := s.sum(weights)
 	if sumWeights <= 0 {
-		return -1, fmt.Errorf("sum(weights) must be positive. got=%0.2f", sumWeights)
+		return -1, Errorf(ErrInvalidProbs, "sum(weights) must be positive. got=%0.2f", sumWeights)
 	}
 
 	curBucket := -1.0
+	lastPositive := -1
 
 	for i, w := range weights {
-		if w < 0 {
-			return -1, fmt.Errorf("negative weight")
+		if w > 0 {
+			lastPositive = i
 		}
 		curBucket += w * float64(s.numBuckets) / sumWeights
 		if curBucket >= float64(bucket) {
@@ -61,5 +72,10 @@ func (s *Sha1Sampler) getIndex(weights []float64, bucket int) (int, error) {
 		}
 	}
 
-	return -1, fmt.Errorf("bucket out of range") // this code should be unreachable
+	// Rounding errors in the running sum can leave the last bucket just out of reach.
+	if lastPositive >= 0 {
+		return lastPositive, nil
+	}
+
+	return -1, Errorf(ErrSampler, "bucket out of range") // this code should be unreachable
 }
@@ -1,6 +1,9 @@
 package mab
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestSha1Sampler_getIndex(t *testing.T) {
 	tests := []struct {
@@ -99,6 +102,12 @@ func TestSha1Sampler_getIndex(t *testing.T) {
 			500,
 			2,
 		},
+		{
+			"rounding error in last bucket",
+			[]float64{0.00506989250443005, 0.9763171856833972, 0.01861292181216908},
+			999,
+			2,
+		},
 	}
 
 	s := NewSha1Sampler()
@@ -115,3 +124,25 @@ func TestSha1Sampler_getIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestSha1Sampler_getIndexError(t *testing.T) {
+	tests := map[string][]float64{
+		"negative weight": {0.5, -0.5, 1},
+		"zero sum":        {0, 0},
+		"NaN weight":      {0.5, math.NaN()},
+		"Inf weight":      {0.5, math.Inf(1)},
+		"-Inf weight":     {math.Inf(-1), 1},
+	}
+
+	s := NewSha1Sampler()
+
+	for name, weights := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, bucket := range []int{0, 500, 999} {
+				if actual, err := s.getIndex(weights, bucket); err == nil {
+					t.Errorf("expected error for bucket %d but got index %d", bucket, actual)
+				}
+			}
+		})
+	}
+}
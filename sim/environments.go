@@ -0,0 +1,160 @@
+// Package sim provides a simulation harness for evaluating bandits against environments with known reward means.
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// An Environment generates the bandit context and rewards for each round of a simulation.
+// Environments must be deterministic given the random number generator, so that simulations are reproducible.
+type Environment interface {
+	// NumArms returns the number of arms.
+	NumArms() int
+
+	// Next returns the bandit context for round t and the true expected reward of each arm in that context.
+	Next(t int, rng *rand.Rand) (banditContext interface{}, means []float64)
+
+	// Reward draws a reward for the selected arm, given the true expected rewards for the round.
+	Reward(means []float64, arm int, rng *rand.Rand) float64
+}
+
+// A Validator is an Environment that can check its parameters. Simulation.Run validates Environments that implement
+// it before the first round.
+type Validator interface {
+	Validate() error
+}
+
+// Bernoulli is a non-contextual Environment with binary rewards, where each arm pays 1 with probability Means[arm].
+type Bernoulli struct {
+	Means []float64
+}
+
+func (b Bernoulli) NumArms() int { return len(b.Means) }
+
+func (b Bernoulli) Next(t int, rng *rand.Rand) (interface{}, []float64) {
+	return nil, b.Means
+}
+
+func (b Bernoulli) Reward(means []float64, arm int, rng *rand.Rand) float64 {
+	return bernoulli(means[arm], rng)
+}
+
+// Gaussian is a non-contextual Environment with normally-distributed rewards with mean Means[arm] and standard
+// deviation Sigma.
+type Gaussian struct {
+	Means []float64
+	Sigma float64
+}
+
+func (g Gaussian) NumArms() int { return len(g.Means) }
+
+func (g Gaussian) Next(t int, rng *rand.Rand) (interface{}, []float64) {
+	return nil, g.Means
+}
+
+func (g Gaussian) Reward(means []float64, arm int, rng *rand.Rand) float64 {
+	return means[arm] + g.Sigma*rng.NormFloat64()
+}
+
+// Drifting is a non-contextual Environment with binary rewards whose means change over time.
+// The means move linearly from each of the Phases to the next over PhaseLength rounds, and cycle back to the first
+// phase after the last one. If Abrupt is true, the means jump from one phase to the next instead.
+// There must be at least one phase, all with the same number of arms, and PhaseLength must be positive.
+type Drifting struct {
+	Phases      [][]float64
+	PhaseLength int
+	Abrupt      bool
+}
+
+// Validate returns an error if there are no Phases, if the Phases have different numbers of arms, or if PhaseLength
+// is not positive.
+func (d Drifting) Validate() error {
+	if len(d.Phases) == 0 {
+		return fmt.Errorf("drifting environment must have at least one phase")
+	}
+	for i, phase := range d.Phases {
+		if len(phase) != len(d.Phases[0]) {
+			return fmt.Errorf("phase %d has %d arms. expected %d", i, len(phase), len(d.Phases[0]))
+		}
+	}
+	if d.PhaseLength <= 0 {
+		return fmt.Errorf("phase length must be positive. got=%d", d.PhaseLength)
+	}
+	return nil
+}
+
+func (d Drifting) NumArms() int { return len(d.Phases[0]) }
+
+func (d Drifting) Next(t int, rng *rand.Rand) (interface{}, []float64) {
+	phase := (t / d.PhaseLength) % len(d.Phases)
+	from := d.Phases[phase]
+	if d.Abrupt {
+		return nil, from
+	}
+
+	to := d.Phases[(phase+1)%len(d.Phases)]
+	frac := float64(t%d.PhaseLength) / float64(d.PhaseLength)
+
+	means := make([]float64, len(from))
+	for i := range means {
+		means[i] = from[i] + frac*(to[i]-from[i])
+	}
+	return nil, means
+}
+
+func (d Drifting) Reward(means []float64, arm int, rng *rand.Rand) float64 {
+	return bernoulli(means[arm], rng)
+}
+
+// Contextual is an Environment where each round's bandit context is drawn uniformly from Contexts, and the expected
+// reward of each arm in Contexts[i] is Means[i][arm].
+// Contexts can be strings for use with a BayesianSource, or feature vectors for use with a linear or logistic model.
+// Rewards are binary if Sigma is zero, and normally distributed with standard deviation Sigma otherwise.
+// There must be a row of Means for each context, all with the same number of arms.
+type Contextual struct {
+	Contexts []interface{}
+	Means    [][]float64
+	Sigma    float64
+}
+
+// Validate returns an error if there are no Contexts, if there is not a row of Means for each context, or if the rows
+// are empty or have different numbers of arms.
+func (c Contextual) Validate() error {
+	if len(c.Contexts) == 0 {
+		return fmt.Errorf("contextual environment must have at least one context")
+	}
+	if len(c.Means) != len(c.Contexts) {
+		return fmt.Errorf("contextual environment has %d contexts but %d rows of means", len(c.Contexts), len(c.Means))
+	}
+	for i, row := range c.Means {
+		if len(row) == 0 {
+			return fmt.Errorf("means row %d is empty", i)
+		}
+		if len(row) != len(c.Means[0]) {
+			return fmt.Errorf("means row %d has %d arms. expected %d", i, len(row), len(c.Means[0]))
+		}
+	}
+	return nil
+}
+
+func (c Contextual) NumArms() int { return len(c.Means[0]) }
+
+func (c Contextual) Next(t int, rng *rand.Rand) (interface{}, []float64) {
+	i := rng.Intn(len(c.Contexts))
+	return c.Contexts[i], c.Means[i]
+}
+
+func (c Contextual) Reward(means []float64, arm int, rng *rand.Rand) float64 {
+	if c.Sigma == 0 {
+		return bernoulli(means[arm], rng)
+	}
+	return means[arm] + c.Sigma*rng.NormFloat64()
+}
+
+func bernoulli(p float64, rng *rand.Rand) float64 {
+	if rng.Float64() < p {
+		return 1
+	}
+	return 0
+}
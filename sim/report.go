@@ -0,0 +1,119 @@
+package sim
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// RunResult summarizes a single run of a Simulation.
+// CumulativeRegret is the sum over rounds of the difference between the best arm's expected reward and the selected
+// arm's expected reward. BestArmIdentified is true if the arm with the highest selection probability in the final
+// round was the best arm for that round.
+type RunResult struct {
+	Seed              int64   `json:"seed"`
+	CumulativeRegret  float64 `json:"cumulative_regret"`
+	TotalReward       float64 `json:"total_reward"`
+	ArmPulls          []int   `json:"arm_pulls"`
+	BestArmIdentified bool    `json:"best_arm_identified"`
+}
+
+// Report summarizes all the runs of a Simulation.
+// MeanCumulativeRegret has an entry for each round, averaged over runs, and MeanArmPulls has an entry for each arm.
+type Report struct {
+	MeanCumulativeRegret []float64   `json:"mean_cumulative_regret"`
+	MeanArmPulls         []float64   `json:"mean_arm_pulls"`
+	BestArmRate          float64     `json:"best_arm_rate"`
+	Runs                 []RunResult `json:"runs"`
+}
+
+func newReport(rounds, numArms int) Report {
+	return Report{
+		MeanCumulativeRegret: make([]float64, rounds),
+		MeanArmPulls:         make([]float64, numArms),
+	}
+}
+
+func (r *Report) finalize() {
+	n := float64(len(r.Runs))
+
+	for t := range r.MeanCumulativeRegret {
+		r.MeanCumulativeRegret[t] /= n
+	}
+
+	identified := 0
+	for _, run := range r.Runs {
+		for arm, pulls := range run.ArmPulls {
+			r.MeanArmPulls[arm] += float64(pulls) / n
+		}
+		if run.BestArmIdentified {
+			identified++
+		}
+	}
+	r.BestArmRate = float64(identified) / n
+}
+
+// FinalRegret returns the mean cumulative regret after the last round.
+func (r Report) FinalRegret() float64 {
+	if len(r.MeanCumulativeRegret) == 0 {
+		return 0
+	}
+	return r.MeanCumulativeRegret[len(r.MeanCumulativeRegret)-1]
+}
+
+// WriteJSON writes the report as JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the mean cumulative regret curve as CSV, with a header row and columns "round" and
+// "mean_cumulative_regret". Rounds are numbered from 1.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"round", "mean_cumulative_regret"}); err != nil {
+		return err
+	}
+	for t, regret := range r.MeanCumulativeRegret {
+		record := []string{strconv.Itoa(t + 1), strconv.FormatFloat(regret, 'g', -1, 64)}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRunsCSV writes one row per run as CSV, with a header row and columns "seed", "cumulative_regret",
+// "total_reward", "best_arm_identified", and "pulls_<arm>" for each arm.
+func (r Report) WriteRunsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"seed", "cumulative_regret", "total_reward", "best_arm_identified"}
+	for arm := range r.MeanArmPulls {
+		header = append(header, "pulls_"+strconv.Itoa(arm))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, run := range r.Runs {
+		record := []string{
+			strconv.FormatInt(run.Seed, 10),
+			strconv.FormatFloat(run.CumulativeRegret, 'g', -1, 64),
+			strconv.FormatFloat(run.TotalReward, 'g', -1, 64),
+			strconv.FormatBool(run.BestArmIdentified),
+		}
+		for _, pulls := range run.ArmPulls {
+			record = append(record, strconv.Itoa(pulls))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,199 @@
+package sim_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/numint"
+	"github.com/stitchfix/mab/sim"
+	"github.com/stretchr/testify/assert"
+)
+
+func thompson(numArms int) func() *mab.Bandit {
+	return func() *mab.Bandit {
+		return &mab.Bandit{
+			RewardSource: mab.NewBayesianSource(numArms, mab.BetaPrior(1, 1)),
+			Strategy:     mab.NewThompson(numint.NewQuadrature()),
+			Sampler:      mab.NewSha1Sampler(),
+		}
+	}
+}
+
+func uniform(numArms int) func() *mab.Bandit {
+	return func() *mab.Bandit {
+		return &mab.Bandit{
+			RewardSource: mab.NewBayesianSource(numArms, mab.BetaPrior(1, 1)),
+			Strategy:     mab.NewEpsilonGreedy(1),
+			Sampler:      mab.NewSha1Sampler(),
+		}
+	}
+}
+
+func TestSimulation_Bernoulli(t *testing.T) {
+	env := sim.Bernoulli{Means: []float64{0.1, 0.5, 0.2}}
+
+	learned, err := sim.Simulation{Environment: env, NewBandit: thompson(3), Rounds: 500, Runs: 4, Seed: 1}.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	random, err := sim.Simulation{Environment: env, NewBandit: uniform(3), Rounds: 500, Runs: 4, Seed: 1}.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if learned.FinalRegret() >= random.FinalRegret()/2 {
+		t.Errorf("thompson regret %f should be much lower than uniform regret %f", learned.FinalRegret(), random.FinalRegret())
+	}
+	if learned.BestArmRate != 1 {
+		t.Errorf("best arm rate not 1. got=%f", learned.BestArmRate)
+	}
+	if learned.MeanArmPulls[1] < 350 {
+		t.Errorf("best arm should get most pulls. got=%v", learned.MeanArmPulls)
+	}
+	if len(learned.Runs) != 4 || len(learned.MeanCumulativeRegret) != 500 {
+		t.Errorf("unexpected report size: %d runs, %d rounds", len(learned.Runs), len(learned.MeanCumulativeRegret))
+	}
+}
+
+func TestSimulation_Reproducible(t *testing.T) {
+	envs := []sim.Environment{
+		sim.Gaussian{Means: []float64{0.1, 0.5}, Sigma: 0.1},
+		sim.Drifting{Phases: [][]float64{{0.2, 0.8}, {0.8, 0.2}}, PhaseLength: 50},
+		sim.Contextual{Contexts: []interface{}{"us", "uk"}, Means: [][]float64{{0.2, 0.6}, {0.7, 0.1}}},
+	}
+
+	for _, env := range envs {
+		newBandit := func() *mab.Bandit {
+			return &mab.Bandit{
+				RewardSource: mab.NewBayesianSource(2, mab.NormalInverseGammaPrior(0, 1, 1, 1)),
+				Strategy:     mab.NewUCB(1),
+				Sampler:      mab.NewSha1Sampler(),
+			}
+		}
+		s := sim.Simulation{Environment: env, NewBandit: newBandit, Rounds: 200, Runs: 2, Seed: 7}
+
+		r1, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !assert.ObjectsAreEqual(r1, r2) {
+			t.Errorf("%T: reports not equal for the same seed", env)
+		}
+	}
+}
+
+func TestSimulation_Drifting(t *testing.T) {
+	env := sim.Drifting{Phases: [][]float64{{0.2, 0.8}, {0.8, 0.2}}, PhaseLength: 300, Abrupt: true}
+
+	newBandit := func(prior mab.Prior) func() *mab.Bandit {
+		return func() *mab.Bandit {
+			return &mab.Bandit{
+				RewardSource: mab.NewBayesianSource(2, prior),
+				Strategy:     mab.NewThompson(numint.NewQuadrature()),
+				Sampler:      mab.NewSha1Sampler(),
+			}
+		}
+	}
+
+	stationary, err := sim.Simulation{Environment: env, NewBandit: newBandit(mab.BetaPrior(1, 1)), Rounds: 600, Runs: 2, Seed: 3}.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	windowed, err := sim.Simulation{Environment: env, NewBandit: newBandit(mab.CountWindowPrior(mab.BetaPrior(1, 1), 50)), Rounds: 600, Runs: 2, Seed: 3}.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if windowed.FinalRegret() >= stationary.FinalRegret() {
+		t.Errorf("windowed regret %f should be lower than stationary regret %f", windowed.FinalRegret(), stationary.FinalRegret())
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	env := sim.Bernoulli{Means: []float64{0.1, 0.5}}
+	report, err := sim.Simulation{Environment: env, NewBandit: uniform(2), Rounds: 3, Runs: 2, Seed: 1}.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || lines[0] != "round,mean_cumulative_regret" {
+		t.Errorf("unexpected csv: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteRunsCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != "seed,cumulative_regret,total_reward,best_arm_identified,pulls_0,pulls_1" {
+		t.Errorf("unexpected csv: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded sim.Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.ObjectsAreEqual(report, decoded) {
+		t.Errorf("json report not %+v. got=%+v", report, decoded)
+	}
+}
+
+func TestSimulation_InvalidEnvironment(t *testing.T) {
+	newBandit := func() *mab.Bandit {
+		return &mab.Bandit{
+			RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+			Strategy:     mab.NewEpsilonGreedy(0.1),
+			Sampler:      mab.NewSha1Sampler(),
+		}
+	}
+
+	for _, env := range []sim.Environment{
+		sim.Drifting{PhaseLength: 50},
+		sim.Drifting{Phases: [][]float64{{0.2, 0.8}, {0.8, 0.2}}},
+		sim.Drifting{Phases: [][]float64{{0.2, 0.8}, {0.8}}, PhaseLength: 50},
+		sim.Contextual{},
+		sim.Contextual{Contexts: []interface{}{"us", "uk"}, Means: [][]float64{{0.2, 0.8}}},
+		sim.Contextual{Contexts: []interface{}{"us", "uk"}, Means: [][]float64{{0.2, 0.8}, {0.8}}},
+		sim.Contextual{Contexts: []interface{}{"us"}, Means: [][]float64{{}}},
+	} {
+		s := sim.Simulation{Environment: env, NewBandit: newBandit, Rounds: 10, Runs: 1}
+		if _, err := s.Run(context.Background()); err == nil {
+			t.Errorf("expected error for %+v but didn't get one", env)
+		}
+	}
+}
+
+func TestSimulation_ArmOutOfRange(t *testing.T) {
+	newBandit := func() *mab.Bandit {
+		return &mab.Bandit{
+			RewardSource: &mab.RewardStub{Rewards: []mab.Dist{mab.Point(0), mab.Point(0), mab.Point(1)}},
+			Strategy:     mab.NewEpsilonGreedy(0),
+			Sampler:      mab.NewSha1Sampler(),
+		}
+	}
+
+	s := sim.Simulation{Environment: sim.Bernoulli{Means: []float64{0.2, 0.8}}, NewBandit: newBandit, Rounds: 10, Runs: 1}
+	if _, err := s.Run(context.Background()); err == nil {
+		t.Error("expected error for an arm the environment does not have but didn't get one")
+	}
+}
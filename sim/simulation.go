@@ -0,0 +1,100 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/stitchfix/mab"
+)
+
+// A Simulation runs a bandit against an Environment for a number of rounds, repeated over several runs.
+// NewBandit is called at the start of every run, so that learning reward sources start from scratch each time.
+// Run i uses the seed Seed+i for the Environment, and the units passed to SelectArm are derived from the seed and
+// round, so a Simulation is fully reproducible from its Seed as long as the bandit itself is deterministic.
+// After each round, the reward is fed back to the bandit with Bandit.Observe.
+type Simulation struct {
+	Environment Environment
+	NewBandit   func() *mab.Bandit
+	Rounds      int
+	Runs        int
+	Seed        int64
+}
+
+// Run runs the simulation and reports the regret, arm pulls and best-arm identification rate averaged over runs.
+// Returns an error if the Environment is not valid, or if the bandit returns an error or an arm that the Environment
+// does not have for any round.
+func (s Simulation) Run(ctx context.Context) (Report, error) {
+	if s.Rounds <= 0 || s.Runs <= 0 {
+		return Report{}, fmt.Errorf("rounds and runs must be positive. got rounds=%d, runs=%d", s.Rounds, s.Runs)
+	}
+	if v, ok := s.Environment.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return Report{}, fmt.Errorf("invalid environment: %w", err)
+		}
+	}
+
+	report := newReport(s.Rounds, s.Environment.NumArms())
+
+	for i := 0; i < s.Runs; i++ {
+		run, err := s.run(ctx, s.Seed+int64(i), report.MeanCumulativeRegret)
+		if err != nil {
+			return report, err
+		}
+		report.Runs = append(report.Runs, run)
+	}
+
+	report.finalize()
+	return report, nil
+}
+
+// run simulates a single run, adding its cumulative regret for each round to regretCurve.
+func (s Simulation) run(ctx context.Context, seed int64, regretCurve []float64) (RunResult, error) {
+	rng := rand.New(rand.NewSource(seed))
+	bandit := s.NewBandit()
+
+	result := RunResult{
+		Seed:     seed,
+		ArmPulls: make([]int, s.Environment.NumArms()),
+	}
+
+	for t := 0; t < s.Rounds; t++ {
+		banditContext, means := s.Environment.Next(t, rng)
+
+		unit := fmt.Sprintf("%d:%d", seed, t)
+		res, err := bandit.SelectArm(ctx, unit, banditContext)
+		if err != nil {
+			return result, fmt.Errorf("seed %d round %d: %w", seed, t, err)
+		}
+		if res.Arm < 0 || res.Arm >= len(result.ArmPulls) {
+			return result, fmt.Errorf("seed %d round %d: arm index %d out of range [0, %d)", seed, t, res.Arm, len(result.ArmPulls))
+		}
+
+		reward := s.Environment.Reward(means, res.Arm, rng)
+		if err := bandit.Observe(ctx, banditContext, res.Arm, reward, res.Propensity()); err != nil {
+			return result, fmt.Errorf("seed %d round %d: %w", seed, t, err)
+		}
+
+		best := argMax(means)
+		result.ArmPulls[res.Arm]++
+		result.TotalReward += reward
+		result.CumulativeRegret += means[best] - means[res.Arm]
+		regretCurve[t] += result.CumulativeRegret
+
+		if t == s.Rounds-1 {
+			result.BestArmIdentified = argMax(res.Probs) == best
+		}
+	}
+
+	return result, nil
+}
+
+func argMax(vals []float64) int {
+	best := 0
+	for i, v := range vals {
+		if v > vals[best] {
+			best = i
+		}
+	}
+	return best
+}
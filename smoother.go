@@ -0,0 +1,227 @@
+package mab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// NewSmoother returns a Smoother that limits the probabilities computed by strategy to move by at most maxStep per
+// arm in each period of length per, with any SmootherOption arguments applied.
+// For example, to let each arm's probability move by at most 5 percentage points per minute:
+//	strategy := NewSmoother(NewThompson(numint.NewQuadrature()), 0.05, time.Minute)
+func NewSmoother(strategy Strategy, maxStep float64, per time.Duration, opts ...SmootherOption) *Smoother {
+	s := &Smoother{
+		strategy: strategy,
+		maxStep:  maxStep,
+		per:      per,
+		clock:    SystemClock,
+		keyFunc:  encodedKey,
+		last:     make(map[interface{}]smoothedProbs),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Smoother is a Strategy decorator that limits how fast the selection probabilities can change when the reward
+// estimates are updated, so that downstream traffic shifts gradually.
+// Each call moves the last probabilities emitted for the bandit context towards the probabilities of the wrapped
+// Strategy, so that no arm's probability changes by more than the max step per period of elapsed time.
+// Arms that become Null get zero probability immediately, and their share is redistributed in proportion to the other
+// arms' last probabilities. The first call for a bandit context, or a call with a different number of arms, returns the
+// wrapped Strategy's probabilities unchanged.
+// The last probabilities are kept for each bandit context when the Smoother is used as the Strategy of a Bandit, which
+// calls ComputeContextualProbs. ComputeProbs uses the state of the nil bandit context.
+// A bandit context's last probabilities are dropped once they are old enough for the max step to allow any change, that
+// is, after per/maxStep, so the state only grows with the number of bandit contexts seen recently.
+// Observed rewards are passed on to the wrapped Strategy if it is a Learner.
+// A Smoother is safe for concurrent use if the wrapped Strategy is.
+type Smoother struct {
+	strategy Strategy
+	maxStep  float64
+	per      time.Duration
+	clock    Clock
+	keyFunc  ContextKeyFunc
+
+	mu        sync.Mutex
+	last      map[interface{}]smoothedProbs
+	lastSweep time.Time
+}
+
+type smoothedProbs struct {
+	probs []float64
+	time  time.Time
+}
+
+// SmootherOption allows for optional arguments to NewSmoother.
+type SmootherOption func(*Smoother)
+
+// WithSmoothingClock sets the Clock used to measure the time between calls. The default is SystemClock.
+func WithSmoothingClock(c Clock) SmootherOption {
+	return func(s *Smoother) {
+		s.clock = c
+	}
+}
+
+// WithSmoothingKey sets the function used to convert a banditContext into a key for the last emitted probabilities.
+// By default, a comparable banditContext, such as a string or nil, is used as the key itself. Other bandit contexts,
+// such as a []float64 feature vector or a map[string]interface{}, are keyed by their type and JSON encoding, or by their
+// Go-syntax representation if they cannot be encoded as JSON.
+func WithSmoothingKey(f ContextKeyFunc) SmootherOption {
+	return func(s *Smoother) {
+		s.keyFunc = f
+	}
+}
+
+// contextEncoding is the key for a bandit context that is not comparable. The type is included so that, for example,
+// a []string and a []int with the same JSON encoding get different keys.
+type contextEncoding struct {
+	typ, enc string
+}
+
+// encodedKey is the default ContextKeyFunc of a Smoother. Map keys are sorted by both encoding/json and fmt, so equal
+// maps get equal keys.
+func encodedKey(banditContext interface{}) (interface{}, error) {
+	if banditContext == nil || reflect.TypeOf(banditContext).Comparable() {
+		return banditContext, nil
+	}
+	typ := fmt.Sprintf("%T", banditContext)
+	if data, err := json.Marshal(banditContext); err == nil {
+		return contextEncoding{typ, string(data)}, nil
+	}
+	return contextEncoding{typ, fmt.Sprintf("%#v", banditContext)}, nil
+}
+
+// ComputeProbs computes smoothed probabilities for the nil bandit context.
+func (s *Smoother) ComputeProbs(rewards []Dist) ([]float64, error) {
+	return s.ComputeContextualProbs(context.Background(), nil, rewards)
+}
+
+// ComputeContextualProbs computes the wrapped Strategy's probabilities and limits their change from the last
+// probabilities emitted for the banditContext.
+// Returns an error if the max step or the period is not positive, or if the key function returns an error for the
+// banditContext.
+func (s *Smoother) ComputeContextualProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	return s.computeProbs(ctx, banditContext, rewards, true)
+}
+
+// PeekProbs returns the probabilities that ComputeContextualProbs would return for the banditContext, without
+// recording them as the last probabilities emitted for it. The wrapped Strategy's probabilities are computed with
+// PeekProbs.
+func (s *Smoother) PeekProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	return s.computeProbs(ctx, banditContext, rewards, false)
+}
+
+func (s *Smoother) computeProbs(ctx context.Context, banditContext interface{}, rewards []Dist, record bool) ([]float64, error) {
+	if !(s.maxStep > 0) {
+		return nil, Errorf(ErrInvalidParameter, "invalid max step value: %v. Must be greater than 0", s.maxStep)
+	}
+	if s.per <= 0 {
+		return nil, Errorf(ErrInvalidParameter, "invalid period: %v. Must be greater than 0", s.per)
+	}
+
+	key, err := s.keyFunc(banditContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var compute computeFunc = ComputeProbs
+	if !record {
+		compute = PeekProbs
+	}
+	target, err := compute(ctx, s.strategy, banditContext, rewards)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	probs := target
+	if last, ok := s.last[key]; ok && len(last.probs) == len(target) {
+		maxStep := s.maxStep * math.Max(0, float64(now.Sub(last.time))) / float64(s.per)
+		probs = smooth(withoutNulls(last.probs, rewards), target, maxStep)
+	}
+
+	if record {
+		s.sweep(now)
+		s.last[key] = smoothedProbs{probs: append([]float64(nil), probs...), time: now}
+	}
+	return probs, nil
+}
+
+// sweep drops the last probabilities that are older than the time it takes to move by the largest possible change,
+// since smoothing from them would return the target unchanged. It scans the state at most once per that time.
+func (s *Smoother) sweep(now time.Time) {
+	ttl := float64(s.per) / s.maxStep
+	if ttl >= math.MaxInt64 || float64(now.Sub(s.lastSweep)) < ttl {
+		return
+	}
+	for key, last := range s.last {
+		if float64(now.Sub(last.time)) >= ttl {
+			delete(s.last, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Observe passes an observed reward to the wrapped Strategy if it is a Learner, and otherwise does nothing.
+func (s *Smoother) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l, ok := s.strategy.(Learner)
+	if !ok {
+		return nil
+	}
+	return l.Observe(ctx, banditContext, arm, reward, propensity)
+}
+
+// withoutNulls returns a copy of probs with Null arms set to zero and the rest rescaled to the original total.
+// Returns nil if there is no probability left on the arms that are not Null.
+func withoutNulls(probs []float64, rewards []Dist) []float64 {
+	result := make([]float64, len(probs))
+	total, remaining := 0.0, 0.0
+	for i, p := range probs {
+		total += p
+		if !math.IsInf(rewards[i].Mean(), -1) {
+			result[i] = p
+			remaining += p
+		}
+	}
+	if remaining == 0 {
+		return nil
+	}
+	for i := range result {
+		result[i] *= total / remaining
+	}
+	return result
+}
+
+// smooth moves from towards to along a straight line, as far as possible without any element changing by more than
+// maxStep. Since the result is a convex combination of the two vectors, it is a valid probability vector if both are.
+func smooth(from, to []float64, maxStep float64) []float64 {
+	if from == nil {
+		return to
+	}
+
+	maxDiff := 0.0
+	for i := range to {
+		maxDiff = math.Max(maxDiff, math.Abs(to[i]-from[i]))
+	}
+	if maxDiff <= maxStep {
+		return to
+	}
+
+	lambda := maxStep / maxDiff
+	result := make([]float64, len(to))
+	for i := range to {
+		result[i] = from[i] + lambda*(to[i]-from[i])
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package mab
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSmoother_sweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewSmoother(NewEpsilonGreedy(0), 0.1, time.Minute, WithSmoothingClock(ClockFunc(func() time.Time { return now })))
+	rewards := []Dist{Point(1), Point(0)}
+
+	for _, banditContext := range []string{"us", "uk"} {
+		if _, err := s.ComputeContextualProbs(context.Background(), banditContext, rewards); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// after 10 minutes, any change is allowed, so the old state is dropped
+	now = now.Add(9 * time.Minute)
+	if _, err := s.ComputeContextualProbs(context.Background(), "us", rewards); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.last) != 2 {
+		t.Errorf("expected 2 bandit contexts before 10 minutes. got=%d", len(s.last))
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := s.ComputeContextualProbs(context.Background(), "de", rewards); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.last["uk"]; ok || len(s.last) != 2 {
+		t.Errorf("expected the state of uk to be dropped. got=%v", s.last)
+	}
+}
@@ -1,8 +1,6 @@
 package mab
 
-import (
-	"sync"
-)
+import "sync"
 
 func NewThompson(integrator Integrator) *Thompson {
 	return &Thompson{
@@ -75,9 +73,9 @@ func (t *Thompson) integrateParallel(integrals []integral) ([]float64, error) {
 
 	wg.Wait()
 
-	for _, err := range errs {
+	for i, err := range errs {
 		if err != nil {
-			return nil, err
+			return nil, Errorf(ErrNotConverged, "arm %d: %w", i, err)
 		}
 	}
 
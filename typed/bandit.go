@@ -0,0 +1,72 @@
+package typed
+
+import (
+	"context"
+
+	"github.com/stitchfix/mab"
+)
+
+// A Bandit is a mab.Bandit whose bandit context has type C.
+// It selects arms in exactly the same way as the equivalent mab.Bandit returned by Untyped, which receives the bandit
+// context as an interface{}, so the Overrides, Filters, Logger and Instrumentation are the same as for a mab.Bandit.
+type Bandit[C any] struct {
+	RewardSource RewardSource[C]
+	mab.Strategy
+	mab.Sampler
+
+	Overrides mab.Overrider
+	Holdout   *mab.Holdout
+	Filters   []mab.ArmFilter
+	Logger    mab.DecisionLogger
+
+	Instrumentation mab.Instrumentation
+
+	Name    string
+	Version string
+}
+
+// FromBandit returns a Bandit with the same components as b, and a RewardSource that passes its context to b's
+// RewardSource with Typed.
+func FromBandit[C any](b *mab.Bandit) *Bandit[C] {
+	return &Bandit[C]{
+		RewardSource:    Typed[C](b.RewardSource),
+		Strategy:        b.Strategy,
+		Sampler:         b.Sampler,
+		Overrides:       b.Overrides,
+		Holdout:         b.Holdout,
+		Filters:         b.Filters,
+		Logger:          b.Logger,
+		Instrumentation: b.Instrumentation,
+		Name:            b.Name,
+		Version:         b.Version,
+	}
+}
+
+// Untyped returns a mab.Bandit with the same components as b, and a RewardSource that accepts the context as an
+// interface{} with Untyped. It can be registered with the registry package, or served by the mabhttp package.
+func (b *Bandit[C]) Untyped() *mab.Bandit {
+	return &mab.Bandit{
+		RewardSource:    Untyped(b.RewardSource),
+		Strategy:        b.Strategy,
+		Sampler:         b.Sampler,
+		Overrides:       b.Overrides,
+		Holdout:         b.Holdout,
+		Filters:         b.Filters,
+		Logger:          b.Logger,
+		Instrumentation: b.Instrumentation,
+		Name:            b.Name,
+		Version:         b.Version,
+	}
+}
+
+// SelectArm gets the current reward estimates for banditContext, computes the arm selection probabilities, and selects
+// an arm index. See mab.Bandit.SelectArm.
+func (b *Bandit[C]) SelectArm(ctx context.Context, unit string, banditContext C) (mab.Result, error) {
+	return b.Untyped().SelectArm(ctx, unit, banditContext)
+}
+
+// Observe routes an observed reward to each of the bandit's components that implement Learner or mab.Learner.
+// See mab.Bandit.Observe.
+func (b *Bandit[C]) Observe(ctx context.Context, banditContext C, arm int, reward float64, propensity float64) error {
+	return b.Untyped().Observe(ctx, banditContext, arm, reward, propensity)
+}
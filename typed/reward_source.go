@@ -0,0 +1,122 @@
+// Package typed provides generic counterparts of the mab Bandit and RewardSource, whose bandit context has a static
+// type C instead of interface{}, and adapters between them and the interface{} API.
+package typed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stitchfix/mab"
+)
+
+// A RewardSource provides the current reward estimates for a bandit context of type C.
+// It is the typed counterpart of mab.RewardSource.
+type RewardSource[C any] interface {
+	GetRewards(ctx context.Context, banditContext C) ([]mab.Dist, error)
+}
+
+// RewardSourceFunc is an adapter to allow the use of ordinary functions as a RewardSource.
+type RewardSourceFunc[C any] func(ctx context.Context, banditContext C) ([]mab.Dist, error)
+
+func (f RewardSourceFunc[C]) GetRewards(ctx context.Context, banditContext C) ([]mab.Dist, error) {
+	return f(ctx, banditContext)
+}
+
+// A Learner is a typed bandit component that learns from observed rewards. It is the typed counterpart of mab.Learner.
+type Learner[C any] interface {
+	Observe(ctx context.Context, banditContext C, arm int, reward float64, propensity float64) error
+}
+
+// ContextualRewardStub is a static contextual RewardSource that can be used for testing and development of contextual
+// bandits, with reward estimates for each value of a comparable bandit context.
+type ContextualRewardStub[C comparable] struct {
+	Rewards map[C][]mab.Dist
+}
+
+// GetRewards gets the static rewards for a given banditContext.
+// Returns a mab.ErrInvalidContext error if there are no rewards for the banditContext.
+func (s *ContextualRewardStub[C]) GetRewards(ctx context.Context, banditContext C) ([]mab.Dist, error) {
+	rewards, ok := s.Rewards[banditContext]
+	if !ok {
+		return nil, mab.Errorf(mab.ErrInvalidContext, "no distributions for %v", banditContext)
+	}
+	return rewards, nil
+}
+
+// Untyped returns a mab.RewardSource that gets reward estimates from source.
+// The banditContext passed to the mab.RewardSource must be a C, or nil for the zero value of C. Any other value is a
+// mab.ErrInvalidContext error. If source is a Learner, observed rewards are passed on to it in the same way.
+func Untyped[C any](source RewardSource[C]) mab.RewardSource {
+	if t, ok := source.(*typedSource[C]); ok {
+		return t.source
+	}
+	return &untypedSource[C]{source}
+}
+
+// Typed returns a RewardSource that passes its banditContext to a mab.RewardSource as an interface{}.
+// If source is a mab.Learner, observed rewards are passed on to it in the same way.
+// For example, to get compile-time typing of the context sent to an HTTP reward service:
+//	source := typed.Typed[Features](mab.NewHTTPSource(client, url, parser))
+func Typed[C any](source mab.RewardSource) RewardSource[C] {
+	if u, ok := source.(*untypedSource[C]); ok {
+		return u.source
+	}
+	return &typedSource[C]{source}
+}
+
+type untypedSource[C any] struct {
+	source RewardSource[C]
+}
+
+func (u *untypedSource[C]) GetRewards(ctx context.Context, banditContext interface{}) ([]mab.Dist, error) {
+	c, err := assertContext[C](banditContext)
+	if err != nil {
+		return nil, err
+	}
+	return u.source.GetRewards(ctx, c)
+}
+
+func (u *untypedSource[C]) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l, ok := u.source.(Learner[C])
+	if !ok {
+		return nil
+	}
+	c, err := assertContext[C](banditContext)
+	if err != nil {
+		return err
+	}
+	return l.Observe(ctx, c, arm, reward, propensity)
+}
+
+type typedSource[C any] struct {
+	source mab.RewardSource
+}
+
+func (t *typedSource[C]) GetRewards(ctx context.Context, banditContext C) ([]mab.Dist, error) {
+	return t.source.GetRewards(ctx, banditContext)
+}
+
+func (t *typedSource[C]) Observe(ctx context.Context, banditContext C, arm int, reward float64, propensity float64) error {
+	l, ok := t.source.(mab.Learner)
+	if !ok {
+		return nil
+	}
+	return l.Observe(ctx, banditContext, arm, reward, propensity)
+}
+
+// assertContext converts an untyped bandit context to C. A nil banditContext is the zero value of C.
+func assertContext[C any](banditContext interface{}) (C, error) {
+	var c C
+	if banditContext == nil {
+		return c, nil
+	}
+	c, ok := banditContext.(C)
+	if !ok {
+		return c, mab.Errorf(mab.ErrInvalidContext, "banditContext must be %s. got=%T", typeName[C](), banditContext)
+	}
+	return c, nil
+}
+
+func typeName[C any]() string {
+	return fmt.Sprintf("%T", (*C)(nil))[1:]
+}
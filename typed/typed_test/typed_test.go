@@ -0,0 +1,140 @@
+package typed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stitchfix/mab"
+	"github.com/stitchfix/mab/typed"
+)
+
+type region struct {
+	Country string
+	Mobile  bool
+}
+
+func TestBandit_SelectArm(t *testing.T) {
+	b := &typed.Bandit[region]{
+		RewardSource: &typed.ContextualRewardStub[region]{Rewards: map[region][]mab.Dist{
+			{"us", false}: {mab.Point(0.2), mab.Point(0.8)},
+			{"us", true}:  {mab.Point(0.9), mab.Point(0.1)},
+		}},
+		Strategy: mab.NewEpsilonGreedy(0),
+		Sampler:  mab.NewSha1Sampler(),
+	}
+
+	for ctx, expected := range map[region]int{{"us", false}: 1, {"us", true}: 0} {
+		res, err := b.SelectArm(context.Background(), "12345", ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Arm != expected {
+			t.Errorf("%v: expected arm %d. got=%d", ctx, expected, res.Arm)
+		}
+	}
+
+	_, err := b.SelectArm(context.Background(), "12345", region{"fr", false})
+	if !errors.Is(err, mab.ErrInvalidContext) {
+		t.Errorf("expected ErrInvalidContext for unknown context. got=%v", err)
+	}
+}
+
+func TestUntyped(t *testing.T) {
+	stub := &typed.ContextualRewardStub[string]{Rewards: map[string][]mab.Dist{
+		"":   {mab.Point(0)},
+		"us": {mab.Point(1)},
+	}}
+	source := typed.Untyped[string](stub)
+
+	rewards, err := source.GetRewards(context.Background(), "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards[0] != mab.Point(1) {
+		t.Errorf("unexpected rewards: %v", rewards)
+	}
+
+	rewards, err = source.GetRewards(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards[0] != mab.Point(0) {
+		t.Errorf("expected nil to be the zero value. got=%v", rewards)
+	}
+
+	_, err = source.GetRewards(context.Background(), 42)
+	if !errors.Is(err, mab.ErrInvalidContext) || err.Error() != "banditContext must be string. got=int" {
+		t.Errorf("unexpected error for wrong context type: %v", err)
+	}
+
+	if typed.Typed[string](source) != typed.RewardSource[string](stub) {
+		t.Error("expected Typed to unwrap Untyped")
+	}
+}
+
+func TestTyped(t *testing.T) {
+	stub := &mab.ContextualRewardStub{Rewards: map[string][]mab.Dist{"us": {mab.Point(1)}}}
+	source := typed.Typed[string](stub)
+
+	rewards, err := source.GetRewards(context.Background(), "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards[0] != mab.Point(1) {
+		t.Errorf("unexpected rewards: %v", rewards)
+	}
+
+	if typed.Untyped(source) != mab.RewardSource(stub) {
+		t.Error("expected Untyped to unwrap Typed")
+	}
+}
+
+func TestBandit_Observe(t *testing.T) {
+	untyped := &mab.Bandit{
+		RewardSource: mab.NewBayesianSource(2, mab.BetaPrior(1, 1)),
+		Strategy:     mab.NewEpsilonGreedy(0),
+		Sampler:      mab.NewSha1Sampler(),
+		Name:         "learning",
+	}
+	b := typed.FromBandit[string](untyped)
+
+	for i := 0; i < 3; i++ {
+		if err := b.Observe(context.Background(), "us", 1, 1, 0.5); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := b.SelectArm(context.Background(), "12345", "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Arm != 1 || res.Rewards[1] != mab.Beta(4, 1) {
+		t.Errorf("expected observations to update the bandit. got=%+v", res)
+	}
+
+	// the round trip shares the underlying reward source
+	res, err = b.Untyped().SelectArm(context.Background(), "12345", "us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Rewards[1] != mab.Beta(4, 1) || b.Untyped().Name != "learning" {
+		t.Errorf("unexpected result from untyped bandit: %+v", res)
+	}
+}
+
+func TestRewardSourceFunc(t *testing.T) {
+	var got int
+	source := typed.RewardSourceFunc[int](func(ctx context.Context, n int) ([]mab.Dist, error) {
+		got = n
+		return make([]mab.Dist, n), nil
+	})
+
+	rewards, err := typed.Untyped[int](source).GetRewards(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 || len(rewards) != 3 {
+		t.Errorf("context not passed to the func. got=%d", got)
+	}
+}
@@ -0,0 +1,61 @@
+package mab
+
+import "math"
+
+func NewUCB(alpha float64) *UCB {
+	return &UCB{
+		Alpha: alpha,
+	}
+}
+
+// UCB implements the upper-confidence-bound bandit strategy.
+// Each arm is scored by its mean reward estimate plus Alpha standard deviations, and the arm with the highest score
+// is selected with probability one. Ties are split equally between the arms with the highest score.
+// Combined with the reward estimates from a linear model, this is the LinUCB algorithm.
+// Distributions that do not implement StdDev are scored by their mean alone.
+// Null arms have zero selection probability.
+type UCB struct {
+	Alpha float64
+}
+
+// A StdDever is a Dist that provides its standard deviation. The built-in Beta and Normal distributions implement it.
+type StdDever interface {
+	StdDev() float64
+}
+
+// ComputeProbs computes the arm selection probabilities from the upper confidence bound of each arm.
+// Returns an error if Alpha is negative.
+func (u *UCB) ComputeProbs(rewards []Dist) ([]float64, error) {
+	if u.Alpha < 0 || math.IsNaN(u.Alpha) {
+		return nil, Errorf(ErrInvalidParameter, "invalid Alpha value: %v. Must be non-negative", u.Alpha)
+	}
+
+	probs := make([]float64, len(rewards))
+
+	scores := make([]float64, len(rewards))
+	for i, dist := range rewards {
+		scores[i] = u.score(dist)
+	}
+
+	maxArgs := argsMax(scores)
+	if len(maxArgs) == 0 || math.IsInf(scores[maxArgs[0]], -1) {
+		return probs, nil
+	}
+
+	for _, i := range maxArgs {
+		probs[i] = 1 / float64(len(maxArgs))
+	}
+
+	return probs, nil
+}
+
+func (u *UCB) score(dist Dist) float64 {
+	mean := dist.Mean()
+	if math.IsInf(mean, -1) {
+		return mean
+	}
+	if s, ok := dist.(StdDever); ok {
+		return mean + u.Alpha*s.StdDev()
+	}
+	return mean
+}
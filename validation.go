@@ -0,0 +1,178 @@
+package mab
+
+import (
+	"context"
+	"math"
+	"reflect"
+)
+
+// A DistValidator is a Dist that can check its own parameters.
+// All of the built-in Dists implement DistValidator.
+type DistValidator interface {
+	Validate() error
+}
+
+// ValidateDist returns an ErrInvalidDist error if d is nil or has invalid parameters.
+// Dists that implement DistValidator are checked with their Validate method. For other Dists, the mean must not be
+// NaN or positive infinity.
+func ValidateDist(d Dist) error {
+	if d == nil {
+		return Errorf(ErrInvalidDist, "distribution is nil")
+	}
+	if v, ok := d.(DistValidator); ok {
+		return v.Validate()
+	}
+	if mean := d.Mean(); math.IsNaN(mean) || math.IsInf(mean, 1) {
+		return Errorf(ErrInvalidDist, "mean must be finite or -Inf. got=%f", mean)
+	}
+	return nil
+}
+
+func isNull(d Dist) bool {
+	p, ok := d.(PointDist)
+	return ok && math.IsInf(p.Mu, -1)
+}
+
+// An InvalidDistPolicy decides what a ValidatingSource does with an arm whose reward estimate is invalid.
+// It is called with the arm index, the invalid Dist and the validation error, and returns either the Dist to use in
+// its place or an error that fails the call to GetRewards.
+type InvalidDistPolicy func(arm int, dist Dist, err error) (Dist, error)
+
+// FailInvalid returns an InvalidDistPolicy that fails the call to GetRewards if any arm is invalid.
+// It is the default policy of a ValidatingSource.
+func FailInvalid() InvalidDistPolicy {
+	return func(arm int, dist Dist, err error) (Dist, error) {
+		return nil, Errorf(ErrInvalidDist, "arm %d: %w", arm, err)
+	}
+}
+
+// NullInvalid returns an InvalidDistPolicy that replaces invalid arms with Null, so they get selection probability zero.
+func NullInvalid() InvalidDistPolicy {
+	return func(int, Dist, error) (Dist, error) {
+		return Null(), nil
+	}
+}
+
+// SubstitutePrior returns an InvalidDistPolicy that replaces invalid arms with the Dist of a new Posterior from prior,
+// so they are explored as if they had no observations.
+func SubstitutePrior(prior Prior) InvalidDistPolicy {
+	return func(int, Dist, error) (Dist, error) {
+		return prior().Dist(), nil
+	}
+}
+
+// NewValidatingSource returns a ValidatingSource that validates the reward estimates from source, with any
+// ValidationOption arguments applied.
+func NewValidatingSource(source RewardSource, opts ...ValidationOption) *ValidatingSource {
+	v := &ValidatingSource{
+		source: source,
+		policy: FailInvalid(),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// ValidatingSource is a RewardSource that checks the reward estimates of another RewardSource before they reach the
+// Strategy, so that a bad reward payload is reported as an ErrInvalidDist error instead of producing NaN probabilities.
+// Each arm is checked with ValidateDist and against the allowed Dist types, and invalid arms are handled by the
+// InvalidDistPolicy. Null arms are always valid.
+// If the number of arms is set with WithNumArms, a different number of arms always fails, whatever the policy.
+// Observed rewards are passed on to the wrapped RewardSource if it is a Learner.
+type ValidatingSource struct {
+	source  RewardSource
+	numArms int
+	allowed []reflect.Type
+	policy  InvalidDistPolicy
+}
+
+// ValidationOption allows for optional arguments to NewValidatingSource.
+type ValidationOption func(*ValidatingSource)
+
+// WithNumArms sets the number of arms that the reward source must return.
+func WithNumArms(n int) ValidationOption {
+	return func(v *ValidatingSource) {
+		v.numArms = n
+	}
+}
+
+// WithAllowedDists restricts the reward estimates to the types of the given Dists, for example
+// WithAllowedDists(Beta(1, 1)) only allows BetaDists. Null is always allowed.
+func WithAllowedDists(dists ...Dist) ValidationOption {
+	return func(v *ValidatingSource) {
+		for _, d := range dists {
+			v.allowed = append(v.allowed, reflect.TypeOf(d))
+		}
+	}
+}
+
+// WithInvalidDistPolicy sets the policy for invalid arms. The default is FailInvalid.
+func WithInvalidDistPolicy(policy InvalidDistPolicy) ValidationOption {
+	return func(v *ValidatingSource) {
+		v.policy = policy
+	}
+}
+
+// GetRewards gets the reward estimates from the wrapped RewardSource and validates them.
+// The returned slice is a copy if any arm was replaced by the policy.
+func (v *ValidatingSource) GetRewards(ctx context.Context, banditContext interface{}) ([]Dist, error) {
+	rewards, err := v.source.GetRewards(ctx, banditContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.numArms > 0 && len(rewards) != v.numArms {
+		return nil, Errorf(ErrInvalidDist, "expected %d arms. got=%d", v.numArms, len(rewards))
+	}
+
+	var validated []Dist
+	for i, d := range rewards {
+		err := v.validate(d)
+		if err == nil {
+			continue
+		}
+		replacement, err := v.policy(i, d, err)
+		if err != nil {
+			return nil, err
+		}
+		if validated == nil {
+			validated = make([]Dist, len(rewards))
+			copy(validated, rewards)
+		}
+		validated[i] = replacement
+	}
+
+	if validated == nil {
+		return rewards, nil
+	}
+	return validated, nil
+}
+
+func (v *ValidatingSource) validate(d Dist) error {
+	if isNull(d) {
+		return nil
+	}
+	if err := ValidateDist(d); err != nil {
+		return err
+	}
+	if len(v.allowed) == 0 {
+		return nil
+	}
+	t := reflect.TypeOf(d)
+	for _, allowed := range v.allowed {
+		if t == allowed {
+			return nil
+		}
+	}
+	return Errorf(ErrInvalidDist, "distribution type %s is not allowed", t)
+}
+
+// Observe passes an observed reward to the wrapped RewardSource if it is a Learner, and otherwise does nothing.
+func (v *ValidatingSource) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l, ok := v.source.(Learner)
+	if !ok {
+		return nil
+	}
+	return l.Observe(ctx, banditContext, arm, reward, propensity)
+}
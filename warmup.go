@@ -0,0 +1,141 @@
+package mab
+
+import (
+	"context"
+	"math"
+)
+
+func NewWarmup(strategy Strategy, minShare, minSamples float64) *Warmup {
+	return &Warmup{
+		Strategy:   strategy,
+		MinShare:   minShare,
+		MinSamples: minSamples,
+	}
+}
+
+// Warmup is a Strategy wrapper that guarantees new arms a minimum share of traffic until they have enough observations
+// for the wrapped Strategy to estimate them reliably.
+// An arm with n of MinSamples observations gets a floor of MinShare * (1 - n/MinSamples), which falls smoothly to zero as
+// the arm warms up. The floors are reserved first, and the rest of the traffic is split according to the wrapped
+// Strategy, so an arm gets at least its floor whatever the wrapped Strategy computes. If the floors add up to more than
+// one, they are scaled down to add up to one.
+// The number of observations is read from Dists that implement SampleSizer. Other Dists are treated as warmed up.
+// The sample size of a Beta distribution is alpha + beta, which includes the pseudo-observations of the prior, so
+// MinSamples should include them too. For example, with a BetaPrior(1, 1), an arm with no observations has a sample
+// size of 2.
+// Null arms have zero selection probability.
+// Observed rewards are passed on to the wrapped Strategy if it is a Learner.
+type Warmup struct {
+	Strategy   Strategy
+	MinShare   float64
+	MinSamples float64
+}
+
+// A SampleSizer is a Dist that provides the number of observations it is based on. The built-in Beta distribution
+// implements it, and WithSampleSize adds it to any Dist.
+type SampleSizer interface {
+	SampleSize() float64
+}
+
+// ComputeProbs computes the arm selection probabilities of the wrapped Strategy and blends in the warm-up floors.
+// Returns an error if MinShare is not between 0 and 1, or if MinSamples is not positive.
+func (w *Warmup) ComputeProbs(rewards []Dist) ([]float64, error) {
+	return w.ComputeContextualProbs(context.Background(), nil, rewards)
+}
+
+// ComputeContextualProbs is the same as ComputeProbs, but passes the banditContext on to the wrapped Strategy if it is
+// a ContextualStrategy.
+func (w *Warmup) ComputeContextualProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	return w.computeProbs(ctx, banditContext, rewards, ComputeProbs)
+}
+
+// PeekProbs is the same as ComputeContextualProbs, but computes the wrapped Strategy's probabilities with PeekProbs,
+// so that the state of a wrapped Peeker is not updated.
+func (w *Warmup) PeekProbs(ctx context.Context, banditContext interface{}, rewards []Dist) ([]float64, error) {
+	return w.computeProbs(ctx, banditContext, rewards, PeekProbs)
+}
+
+type computeFunc func(ctx context.Context, strategy Strategy, banditContext interface{}, rewards []Dist) ([]float64, error)
+
+func (w *Warmup) computeProbs(ctx context.Context, banditContext interface{}, rewards []Dist, compute computeFunc) ([]float64, error) {
+	if !(w.MinShare >= 0 && w.MinShare <= 1) {
+		return nil, Errorf(ErrInvalidParameter, "invalid MinShare value: %v. Must be between 0 and 1", w.MinShare)
+	}
+	if !(w.MinSamples > 0) {
+		return nil, Errorf(ErrInvalidParameter, "invalid MinSamples value: %v. Must be greater than 0", w.MinSamples)
+	}
+
+	probs, err := compute(ctx, w.Strategy, banditContext, rewards)
+	if err != nil {
+		return nil, err
+	}
+
+	floors := make([]float64, len(rewards))
+	total := 0.0
+	for i, dist := range rewards {
+		floors[i] = w.floor(dist)
+		total += floors[i]
+	}
+
+	if total == 0 {
+		return probs, nil
+	}
+
+	if total > 1 {
+		for i := range floors {
+			floors[i] /= total
+		}
+		return floors, nil
+	}
+
+	blended := make([]float64, len(probs))
+	for i := range probs {
+		blended[i] = floors[i] + (1-total)*probs[i]
+	}
+	return blended, nil
+}
+
+// Observe passes an observed reward to the wrapped Strategy if it is a Learner, and otherwise does nothing.
+func (w *Warmup) Observe(ctx context.Context, banditContext interface{}, arm int, reward float64, propensity float64) error {
+	l, ok := w.Strategy.(Learner)
+	if !ok {
+		return nil
+	}
+	return l.Observe(ctx, banditContext, arm, reward, propensity)
+}
+
+func (w *Warmup) floor(dist Dist) float64 {
+	if math.IsInf(dist.Mean(), -1) {
+		return 0
+	}
+	s, ok := dist.(SampleSizer)
+	if !ok {
+		return 0
+	}
+	remaining := 1 - s.SampleSize()/w.MinSamples
+	if remaining <= 0 {
+		return 0
+	}
+	return w.MinShare * math.Min(remaining, 1)
+}
+
+// WithSampleSize returns a Dist that behaves like dist and implements SampleSizer with the given number of observations.
+func WithSampleSize(dist Dist, n float64) SampledDist {
+	return SampledDist{Dist: dist, N: n}
+}
+
+// SampledDist is a Dist with a known number of observations.
+type SampledDist struct {
+	Dist
+	N float64
+}
+
+// SampleSize returns the number of observations.
+func (s SampledDist) SampleSize() float64 {
+	return s.N
+}
+
+// Validate validates the underlying Dist with ValidateDist.
+func (s SampledDist) Validate() error {
+	return ValidateDist(s.Dist)
+}